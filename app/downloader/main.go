@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/api"
 	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
 	"github.com/urfave/cli/v2"
 	"go-micro.dev/v4"
@@ -21,18 +22,19 @@ func main() {
 	var command string
 	var list uint
 	var torrentId string
+	var conflict string
 	service := micro.NewService(
 		micro.Name("rms-library.downloader"),
 		micro.Flags(
 			&cli.StringFlag{
 				Name:        "command",
-				Usage:       "add,list,delete,move,torrents-list,torrents-delete,torrents-find,torrent-add",
+				Usage:       "add,list,delete,move,torrents-list,torrents-delete,torrents-find,torrent-add,episodes,export,import",
 				Required:    true,
 				Destination: &command,
 			},
 			&cli.StringFlag{
 				Name:        "query",
-				Usage:       "Query for download or media id",
+				Usage:       "Query for download, media id or backup file on the server side",
 				Required:    false,
 				Destination: &query,
 			},
@@ -48,6 +50,13 @@ func main() {
 				Required:    false,
 				Destination: &list,
 			},
+			&cli.StringFlag{
+				Name:        "conflict",
+				Usage:       "Import conflict mode (skip, replace, merge)",
+				Required:    false,
+				Value:       api.ImportSkip,
+				Destination: &conflict,
+			},
 		),
 	)
 	service.Init()
@@ -69,6 +78,12 @@ func main() {
 		torrentsFindCommand(service.Client(), query)
 	case "torrents-add":
 		torrentsAddCommand(service.Client(), query, torrentId)
+	case "episodes":
+		episodesCommand(service.Client(), query)
+	case "export":
+		exportCommand(service.Client(), query)
+	case "import":
+		importCommand(service.Client(), query, conflict)
 	default:
 		panic("unknown command")
 	}
@@ -156,14 +171,19 @@ func torrentsDeleteCommand(cli client.Client, id, tId string) {
 }
 
 func torrentsFindCommand(cli client.Client, id string) {
-	torrents := rms_library.NewTorrentsService("rms-library", cli)
-	list, err := torrents.Find(context.Background(), &rms_library.TorrentsFindRequest{Id: id})
+	ranking := api.NewRankingService("rms-library", cli)
+	list, err := ranking.Explain(context.Background(), &api.RankingExplainRequest{Id: id}, client.WithRequestTimeout(defaultTimeout))
 	if err != nil {
 		panic(err)
 	}
 
 	for i, t := range list.Torrents {
-		fmt.Printf("%d. %s [ %s ] seeders:%d, %d Mb\n", i+1, t.Title, t.Id, t.Seeders, t.Size)
+		fmt.Printf("%d. %s [ %s ] seeders:%d, %d Mb, %s, %s\n", i+1, t.Title, t.Id, t.Seeders, t.Size, t.Quality, t.Voice)
+		fmt.Printf("\trank: %.4f", t.Rank)
+		for _, s := range t.Scores {
+			fmt.Printf(", %s: %.4f x %.1f", s.Name, s.Rank, s.Weight)
+		}
+		fmt.Println()
 	}
 }
 
@@ -175,3 +195,35 @@ func torrentsAddCommand(cli client.Client, id, tId string) {
 		panic(err)
 	}
 }
+
+func episodesCommand(cli client.Client, id string) {
+	episodes := api.NewEpisodesService("rms-library", cli)
+	resp, err := episodes.List(context.Background(), &api.EpisodesListRequest{Id: id})
+	if err != nil {
+		panic(err)
+	}
+
+	for _, s := range resp.Seasons {
+		fmt.Printf("Season %d: %d episodes %+v, missing %+v, pending %+v\n", s.No, len(s.Episodes), s.Episodes, s.Missing, s.Pending)
+	}
+}
+
+func exportCommand(cli client.Client, fileName string) {
+	backup := api.NewBackupService("rms-library", cli)
+	resp, err := backup.Export(context.Background(), &api.BackupExportRequest{Path: fileName}, client.WithRequestTimeout(defaultTimeout))
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("Exported %d items, %d files to %s\n", resp.Items, resp.Files, resp.Path)
+}
+
+func importCommand(cli client.Client, fileName string, conflict string) {
+	backup := api.NewBackupService("rms-library", cli)
+	resp, err := backup.Import(context.Background(), &api.BackupImportRequest{Path: fileName, Conflict: conflict}, client.WithRequestTimeout(defaultTimeout))
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("Added %d, replaced %d, merged %d, skipped %d, files %d\n", resp.Added, resp.Replaced, resp.Merged, resp.Skipped, resp.Files)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/selector/corpus"
+)
+
+func main() {
+	dir := flag.String("corpus", "pkg/selector/testdata/corpus", "Directory with corpus of cases")
+	verbose := flag.Bool("v", false, "Print scores of passed cases too")
+	flag.Parse()
+
+	cases, err := corpus.Load(*dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Load corpus failed: %s\n", err)
+		os.Exit(2)
+	}
+
+	total, failed := 0, 0
+	for _, c := range cases {
+		for _, o := range c.Replay() {
+			total++
+			if o.Passed() {
+				fmt.Printf("PASS %s [%s]\n", o.Case, o.Criteria)
+				if *verbose {
+					printCandidate("picked", o.Actual)
+				}
+				continue
+			}
+			failed++
+			fmt.Printf("FAIL %s [%s]\n", o.Case, o.Criteria)
+			printCandidate("expected", o.Expected)
+			printCandidate("actual", o.Actual)
+		}
+	}
+
+	fmt.Printf("\n%d of %d passed\n", total-failed, total)
+	if failed != 0 {
+		os.Exit(1)
+	}
+}
+
+func printCandidate(label string, c *corpus.Candidate) {
+	if c == nil {
+		fmt.Printf("\t%-8s <none>\n", label)
+		return
+	}
+	fmt.Printf("\t%-8s #%d %s\n", label, c.Index, c.Title)
+	fmt.Printf("\t%-8s total: %.4f\n", "", c.Explanation.Total)
+	for _, s := range c.Explanation.Scores {
+		fmt.Printf("\t%-8s %s: %.4f x %.2f\n", "", s.Name, s.Rank, s.Weight)
+	}
+}
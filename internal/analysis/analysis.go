@@ -1,6 +1,8 @@
 package analysis
 
 import (
+	"time"
+
 	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
 	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
 )
@@ -13,6 +15,29 @@ type Result struct {
 	Season      uint
 	Episode     int
 	EpisodeName string
+
+	// LastEpisode is the last episode of multi-episode file (S01E01-E03), zero for a single episode
+	LastEpisode int
+
+	// Absolute means Episode is numbered through all seasons, see ApplyNumbering
+	Absolute bool
+
+	// AirDate is a date of the daily show episode, see ApplyNumbering
+	AirDate time.Time
+
+	// Tags are release metadata (quality, source, codec, voices), the file name takes precedence over directories
+	Tags ReleaseTags
+
+	// Language is a language of subtitles or external audio track (rus, eng), empty if unknown
+	Language string
+}
+
+// EpisodeRange returns the first and the last episodes of the file
+func (r Result) EpisodeRange() (first, last int) {
+	if r.LastEpisode > r.Episode {
+		return r.Episode, r.LastEpisode
+	}
+	return r.Episode, r.Episode
 }
 
 func Analyze(fileName string) Result {
@@ -21,15 +46,24 @@ func Analyze(fileName string) Result {
 	subResults := analyzeLayout(layout)
 	result := mergeResults(subResults)
 	if layout.IsVideoFile() {
-		if result.Season != 0 {
+		if result.Season != 0 || (result.Absolute && result.Episode > 0) || !result.AirDate.IsZero() {
 			result.FileType = model.FileTypeEpisode
 		} else {
 			result.FileType = model.FileTypeFilm
 		}
 	}
-	if layout.IsSubtitlesFile() {
+	if layout.IsSubtitlesFile() || layout.IsAudioTrackFile() {
 		result.FileType = model.FileTypeMediaSupply
 	}
+	result.FileType = classify(fileName, layout, &result)
+	if result.FileType == model.FileTypeMediaSupply {
+		result.Language = detectLanguage(fileName)
+	}
+	if result.FileType == model.FileTypeSpecial {
+		result.Season = 0
+		result.Absolute = false
+		result.BelongsTo = rms_library.MovieType_TvSeries
+	}
 	return result
 }
 
@@ -62,9 +96,23 @@ func mergeResults(results []analyzeResult) Result {
 	for i := len(results) - 1; i >= 0; i-- {
 		if result.Episode < 0 {
 			result.Episode = results[i].Episode
+			result.LastEpisode = results[i].LastEpisode
+			result.Absolute = results[i].Absolute
 		}
 	}
 
+	// определяем дату выпуска
+	for i := len(results) - 1; i >= 0; i-- {
+		if result.AirDate.IsZero() {
+			result.AirDate = results[i].AirDate
+		}
+	}
+
+	// собираем теги релиза
+	for i := len(results) - 1; i >= 0; i-- {
+		result.Tags.merge(results[i].Tags)
+	}
+
 	// определяем сезон
 	for i := len(results) - 1; i >= 0; i-- {
 		if result.Season == 0 {
@@ -90,8 +138,13 @@ func mergeResults(results []analyzeResult) Result {
 		}
 	}
 
-	result.BelongsTo = rms_library.MovieType_Film
+	// сезон из имени директории означает обычную нумерацию эпизодов
 	if result.Season != 0 {
+		result.Absolute = false
+	}
+
+	result.BelongsTo = rms_library.MovieType_Film
+	if result.Season != 0 || result.Absolute || !result.AirDate.IsZero() {
 		result.BelongsTo = rms_library.MovieType_TvSeries
 	}
 
@@ -138,9 +138,34 @@ func TestAnalyze(t *testing.T) {
 				EpisodeName: "x Men Tas",
 			},
 		},
+		{
+			input: "Twin Peaks (Season 1)/Twin.Peaks.S01E01E02.Pilot.mkv",
+			output: Result{
+				Titles:      []string{"Twin Peaks"},
+				BelongsTo:   rms_library.MovieType_TvSeries,
+				Season:      1,
+				Episode:     1,
+				LastEpisode: 2,
+				FileType:    model.FileTypeEpisode,
+				EpisodeName: "Twin Peaks",
+			},
+		},
+		{
+			input: "[Erai-raws] Shingeki no Kyojin/[Erai-raws] Shingeki no Kyojin - 137 [1080p].mkv",
+			output: Result{
+				Titles:      []string{"Shingeki no Kyojin"},
+				BelongsTo:   rms_library.MovieType_TvSeries,
+				Episode:     137,
+				FileType:    model.FileTypeEpisode,
+				EpisodeName: "Shingeki no Kyojin",
+				Absolute:    true,
+			},
+		},
 	}
 
 	for i, tc := range testCases {
-		assert.Equal(t, tc.output, Analyze(tc.input), "Test %d failed", i)
+		actual := Analyze(tc.input)
+		actual.Tags = ReleaseTags{} // теги проверяются в TestReleaseTags
+		assert.Equal(t, tc.output, actual, "Test %d failed", i)
 	}
 }
@@ -3,6 +3,7 @@ package analysis
 import (
 	"regexp"
 	"strconv"
+	"time"
 )
 
 type analyzeResult struct {
@@ -10,18 +11,65 @@ type analyzeResult struct {
 	Year    uint
 	Season  uint
 	Episode int
+
+	// LastEpisode is the last episode of range (E01-E02), zero if the name contains a single episode
+	LastEpisode int
+
+	// Absolute means the episode is numbered through all seasons (anime style)
+	Absolute bool
+
+	// AirDate is a date of the daily show episode (Show.2024.03.15)
+	AirDate time.Time
+
+	// Tags are release metadata found in the name
+	Tags ReleaseTags
 }
 
+var (
+	nameSeasonRegex       = regexp.MustCompile(`s\d\d?`)
+	seasonNumberRegex     = regexp.MustCompile(`^\d\d?$`)
+	seasonDigitsRegex     = regexp.MustCompile(`\d\d?`)
+	crossSeasonRegex      = regexp.MustCompile(`(\d+)x\d\d`)
+	absoluteRegex         = regexp.MustCompile(`^\d\d\d?\d?(v\d)?$`)
+	twoDigitsRegex        = regexp.MustCompile(`^\d\d$`)
+	oneDigitRegex         = regexp.MustCompile(`^\d$`)
+	digitsRegex           = regexp.MustCompile(`\d+`)
+	rangeRegex            = regexp.MustCompile(`e(\d\d\d?)`)
+	rangeEndRegex         = regexp.MustCompile(`^e?\d\d$|^e\d\d\d$`)
+	markedNumberRegex     = regexp.MustCompile(`^\d\d?\d?\d?(v\d)?$`)
+	gluedMarkerRegex      = regexp.MustCompile(`^ep\d\d?\d?\d?(v\d)?$`)
+	yearRegex             = regexp.MustCompile(`^\d\d\d\d$`)
+	versionedEpisodeRegex = regexp.MustCompile(`^\d\d?\d?v\d$`)
+)
+
 type analyzeContext struct {
 	result analyzeResult
 	remove []bool
 	name   tokenList
+	rules  *compiledRules
+
+	// reasons explain why tokens were removed from the title
+	reasons []string
+}
+
+func (ctx *analyzeContext) drop(i int, reason string) {
+	ctx.remove[i] = true
+	if ctx.reasons[i] == "" {
+		ctx.reasons[i] = reason
+	}
 }
 
 func analyzeFileName(name tokenList) analyzeResult {
+	result, _ := analyzeFileNameTrace(name)
+	return result
+}
+
+func analyzeFileNameTrace(name tokenList) (analyzeResult, []TraceToken) {
 	ctx := analyzeContext{
-		name:   name,
-		remove: make([]bool, len(name)),
+		name:    name,
+		remove:  make([]bool, len(name)),
+		reasons: make([]string, len(name)),
+		rules:   getRules(),
 	}
 	ctx.result.Episode = -1
 
@@ -34,7 +82,8 @@ func analyzeFileName(name tokenList) analyzeResult {
 	// 2) если не удалось ищем сочетания S01, season01, 01season и пр.
 	determineSeason(&ctx)
 
-	// 3) вытаскиваем год
+	// 3) вытаскиваем дату выпуска (ежедневные шоу) и год
+	determineAirDate(&ctx)
 	determineYear(&ctx)
 
 	// 4) удаляем распознанные лишние слова
@@ -43,6 +92,8 @@ func analyzeFileName(name tokenList) analyzeResult {
 	// 5) определяем эпизод
 	determineEpisode(&ctx)
 
+	trace := ctx.trace()
+
 	// 6) убираем префикс вида [...] Title
 	removePossiblePrefix(&ctx)
 
@@ -50,7 +101,7 @@ func analyzeFileName(name tokenList) analyzeResult {
 	titleLength := guessTitleLength(ctx.name, ctx.remove)
 	ctx.result.Tokens = crop(ctx.name, ctx.remove, titleLength)
 
-	return ctx.result
+	return ctx.result, trace
 }
 
 func determineNameSeasonCase(ctx *analyzeContext) {
@@ -58,12 +109,11 @@ func determineNameSeasonCase(ctx *analyzeContext) {
 		return
 	}
 
-	expr := regexp.MustCompile(`s\d\d?`)
-	found := expr.FindString(ctx.name[0].Text)
+	found := nameSeasonRegex.FindString(ctx.name[0].Text)
 	if found != "" {
 		season, _ := strconv.ParseUint(found[1:], 10, 32)
 		ctx.result.Season = uint(season)
-		idx := expr.FindStringIndex(ctx.name[0].Text)
+		idx := nameSeasonRegex.FindStringIndex(ctx.name[0].Text)
 		tmp := ctx.name[0].Text
 		tmp = tmp[:idx[0]] + tmp[idx[1]:]
 		ctx.name[0].Text = tmp
@@ -71,16 +121,9 @@ func determineNameSeasonCase(ctx *analyzeContext) {
 }
 
 func determineSplitSeason(ctx *analyzeContext) {
-	splitSeasonMatch := &orMatch{
-		Matches: []match{
-			&wordMatch{Word: "сезон"},
-			&wordMatch{Word: "season"},
-			&wordMatch{Word: "sezon"},
-		},
-	}
-	pos := ctx.name.Find(splitSeasonMatch)
+	pos := ctx.name.Find(&wordsMatch{rule: ctx.rules.season})
 	if pos > -1 {
-		m := regexMatch{Exp: regexp.MustCompile(`^\d\d?$`)}
+		m := regexMatch{Exp: seasonNumberRegex}
 		found := -1
 		if pos < len(ctx.name)-1 && m.Match(ctx.name[pos+1]) {
 			found = pos + 1
@@ -94,66 +137,67 @@ func determineSplitSeason(ctx *analyzeContext) {
 		if found > -1 {
 			season, _ := strconv.ParseUint(ctx.name[found].Text, 10, 32)
 			ctx.result.Season = uint(season)
-			ctx.remove[pos] = true
-			ctx.remove[found] = true
+			ctx.drop(pos, "season")
+			ctx.drop(found, "season")
 		}
 	}
 }
 
 func determineSeason(ctx *analyzeContext) {
-	seasonMatch := &orMatch{
-		Matches: []match{
-			&regexMatch{Exp: regexp.MustCompile(`s\d\d?`)},
-			&regexMatch{Exp: regexp.MustCompile(`season\d\d?`)},
-			&regexMatch{Exp: regexp.MustCompile(`сезон\d\d?`)},
-			&regexMatch{Exp: regexp.MustCompile(`\d\d?season`)},
-			&regexMatch{Exp: regexp.MustCompile(`сезон\d\d?`)},
-		},
-	}
-	pos := ctx.name.Find(seasonMatch)
+	pos := ctx.name.Find(&patternsMatch{rule: ctx.rules.season})
 	if pos > -1 {
-		exp := regexp.MustCompile(`\d\d?`)
-		seasonString := exp.FindString(ctx.name[pos].Text)
+		seasonString := seasonDigitsRegex.FindString(ctx.name[pos].Text)
 		if seasonString == "" {
 			return
 		}
 		season, _ := strconv.ParseUint(seasonString, 10, 32)
 		ctx.result.Season = uint(season)
-		ctx.remove[pos] = true
+		ctx.drop(pos, "season")
 	}
 }
 
 func determineEpisode(ctx *analyzeContext) {
-	rmatches := []regexMatch{
-		{Exp: regexp.MustCompile(`e\d\d`)},
-		{Exp: regexp.MustCompile(`x\d\d`)},
-	}
-	for _, m := range rmatches {
-		pos := ctx.name.Find(m)
+	for _, exp := range ctx.rules.episode.patterns {
+		pos := ctx.name.Find(regexMatch{Exp: exp})
 		if pos > -1 {
-			episodeString := m.Exp.FindString(ctx.name[pos].Text)
+			episodeString := exp.FindString(ctx.name[pos].Text)
 			if episodeString == "" {
 				continue
 			}
-			episode, _ := strconv.ParseInt(episodeString[1:], 10, 32)
-			ctx.result.Episode = int(episode)
-			ctx.remove[pos] = true
+			ctx.result.Episode = parseEpisodeNumber(episodeString)
+			ctx.drop(pos, "episode")
 
 			if ctx.result.Season == 0 {
-				exp := regexp.MustCompile(`(\d+)x\d\d`)
-				matches := exp.FindStringSubmatch(ctx.name[pos].Text)
+				matches := crossSeasonRegex.FindStringSubmatch(ctx.name[pos].Text)
 				if len(matches) == 2 {
 					season, _ := strconv.ParseUint(matches[1], 10, 32)
 					ctx.result.Season = uint(season)
 				}
 			}
 
+			determineEpisodeRange(ctx, pos)
+			return
+		}
+	}
+
+	// явное указание эпизода: Ep 05, Ep05v2, Episode 5, Эпизод 12
+	if determineMarkedEpisode(ctx) {
+		return
+	}
+
+	// аниме: [Group] Title - 137 [1080p], Title - 05v2
+	if isAnimeStyle(ctx) && ctx.result.Season == 0 {
+		pos := findEpisodeNumber(ctx, absoluteRegex)
+		if pos > -1 {
+			ctx.result.Episode = parseEpisodeNumber(ctx.name[pos].Text)
+			ctx.result.Absolute = true
+			ctx.drop(pos, "absolute episode")
 			return
 		}
 	}
 
 	pos := 0
-	m := &regexMatch{Exp: regexp.MustCompile(`^\d\d$`)}
+	m := &regexMatch{Exp: twoDigitsRegex}
 	for {
 		cpos := ctx.name[pos:].Find(m)
 		if cpos < 0 {
@@ -170,55 +214,136 @@ func determineEpisode(ctx *analyzeContext) {
 	if pos > -1 {
 		episode, _ := strconv.ParseInt(ctx.name[pos].Text, 10, 32)
 		ctx.result.Episode = int(episode)
-		ctx.remove[pos] = true
+		ctx.drop(pos, "episode")
 		return
 	}
 
-	pos = ctx.name.Find(&regexMatch{Exp: regexp.MustCompile(`^\d$`)})
+	pos = ctx.name.Find(&regexMatch{Exp: oneDigitRegex})
 	if pos > -1 && !ctx.remove[pos] {
 		episode, _ := strconv.ParseInt(ctx.name[pos].Text, 10, 32)
 		ctx.result.Episode = int(episode)
 	}
 }
 
+func isAnimeStyle(ctx *analyzeContext) bool {
+	// раздача начинается с названия группы в скобках, либо номер эпизода содержит версию (05v2)
+	if len(ctx.name) != 0 && ctx.name[0].InBraces {
+		return true
+	}
+	return ctx.name.Find(&regexMatch{Exp: versionedEpisodeRegex}) > -1
+}
+
+func findEpisodeNumber(ctx *analyzeContext, exp *regexp.Regexp) int {
+	for i := range ctx.name {
+		// четырехзначные номера удаляются как год, но год должен быть правдоподобным
+		removed := ctx.remove[i] && (len(ctx.name[i].Text) != 4 || isYear(ctx.name[i].Text))
+		if !removed && !ctx.name[i].InBraces && exp.MatchString(ctx.name[i].Text) {
+			return i
+		}
+	}
+	return -1
+}
+
+func isYear(text string) bool {
+	year, err := strconv.ParseUint(text, 10, 32)
+	return err == nil && year >= 1850 && year <= 2100
+}
+
+func parseEpisodeNumber(text string) int {
+	digits := digitsRegex.FindString(text)
+	episode, _ := strconv.ParseInt(digits, 10, 32)
+	return int(episode)
+}
+
+func determineEpisodeRange(ctx *analyzeContext, pos int) {
+	// S01E01E02 - диапазон внутри одного слова
+	matches := rangeRegex.FindAllStringSubmatch(ctx.name[pos].Text, -1)
+	last := -1
+	if len(matches) > 1 {
+		last = parseEpisodeNumber(matches[len(matches)-1][1])
+	} else if pos+1 < len(ctx.name) && rangeEndRegex.MatchString(ctx.name[pos+1].Text) {
+		// S01E01-E03, S01E01-03
+		last = parseEpisodeNumber(ctx.name[pos+1].Text)
+		if last > ctx.result.Episode {
+			ctx.drop(pos+1, "episode range")
+		}
+	}
+	if last > ctx.result.Episode {
+		ctx.result.LastEpisode = last
+	}
+}
+
+func determineMarkedEpisode(ctx *analyzeContext) bool {
+	for i, t := range ctx.name {
+		found := -1
+		if ctx.rules.episode.isWord(t) && i+1 < len(ctx.name) && markedNumberRegex.MatchString(ctx.name[i+1].Text) {
+			found = i + 1
+		} else if gluedMarkerRegex.MatchString(t.Text) {
+			found = i
+		}
+		if found < 0 {
+			continue
+		}
+
+		ctx.result.Episode = parseEpisodeNumber(ctx.name[found].Text)
+		ctx.result.Absolute = ctx.result.Season == 0
+		ctx.drop(i, "episode marker")
+		ctx.drop(found, "episode")
+		return true
+	}
+	return false
+}
+
+func determineAirDate(ctx *analyzeContext) {
+	for i := 0; i+2 < len(ctx.name); i++ {
+		a, b, c := ctx.name[i].Text, ctx.name[i+1].Text, ctx.name[i+2].Text
+		var year, month, day string
+		switch {
+		case len(a) == 4 && len(b) == 2 && len(c) == 2:
+			year, month, day = a, b, c
+		case len(a) == 2 && len(b) == 2 && len(c) == 4:
+			day, month, year = a, b, c
+		default:
+			continue
+		}
+		if !isYear(year) {
+			continue
+		}
+		date, err := time.Parse(time.DateOnly, year+"-"+month+"-"+day)
+		if err != nil {
+			continue
+		}
+
+		ctx.result.AirDate = date
+		ctx.drop(i, "air date")
+		ctx.drop(i+1, "air date")
+		ctx.drop(i+2, "air date")
+		return
+	}
+}
+
 func determineYear(ctx *analyzeContext) {
-	yearMatch := &regexMatch{Exp: regexp.MustCompile(`^\d\d\d\d$`)}
-	pos := ctx.name.Find(yearMatch)
-	if pos > -1 {
+	pos := ctx.name.Find(&regexMatch{Exp: yearRegex})
+	if pos > -1 && isYear(ctx.name[pos].Text) {
 		year, _ := strconv.ParseUint(ctx.name[pos].Text, 10, 32)
-		ctx.remove[pos] = true
+		ctx.drop(pos, "year")
 		ctx.result.Year = uint(year)
 	}
 }
 
 func removeExtraWords(ctx *analyzeContext) {
-	matched := ctx.name.FindAll(
-		&orMatch{
-			Matches: []match{
-				&bracesMatch{},
-				&wordMatch{Word: "rus"},
-				&wordMatch{Word: "eng"},
-				&wordMatch{Word: "avo"},
-				&wordMatch{Word: "remastered"},
-				&wordMatch{Word: "web"},
-				&wordMatch{Word: "dl"},
-				&wordMatch{Word: "webdl"},
-				&wordMatch{Word: "sub"},
-				&wordMatch{Word: "lostfilm"},
-				&wordMatch{Word: "unrated"},
-				&wordMatch{Word: "dvd"},
-				&wordMatch{Word: "сериал"},
-				&wordMatch{Word: "серия"},
-				&regexMatch{Exp: regexp.MustCompile(`rip$`)},
-				&regexMatch{Exp: regexp.MustCompile(`^\d\d\d\d?p$`)},
-				&regexMatch{Exp: regexp.MustCompile(`^\d\d\d\d$`)},
-				&regexMatch{Exp: regexp.MustCompile(`remux$`)},
-			},
-		},
-	)
-
-	for _, r := range matched {
-		ctx.remove[r] = true
+	for i, t := range ctx.name {
+		class, rule, ok := ctx.rules.tag(t)
+		if ok {
+			ctx.result.Tags.add(class, t.Text)
+		}
+		if t.InBraces {
+			ctx.drop(i, "braces")
+			continue
+		}
+		if ok {
+			ctx.drop(i, "tag "+class+": "+rule)
+		}
 	}
 }
 
@@ -233,6 +358,7 @@ func removePossiblePrefix(ctx *analyzeContext) {
 
 	ctx.name = ctx.name[prefixIndex:]
 	ctx.remove = ctx.remove[prefixIndex:]
+	ctx.reasons = ctx.reasons[prefixIndex:]
 }
 
 func guessTitleLength(name tokenList, remove []bool) int {
@@ -2,6 +2,7 @@ package analysis
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -230,11 +231,115 @@ func TestAnalyzeFileName(t *testing.T) {
 				Episode: -1,
 			},
 		},
+		{
+			input: "[SubsPlease] One Piece - 1071 [1080p]",
+			output: analyzeResult{
+				Tokens: tokenList{
+					token{Text: "one"},
+					token{Text: "piece"},
+				},
+				Episode:  1071,
+				Absolute: true,
+			},
+		},
+		{
+			input: "[Erai-raws] Shingeki no Kyojin - 137 [1080p][Multiple Subtitle]",
+			output: analyzeResult{
+				Tokens: tokenList{
+					token{Text: "shingeki"},
+					token{Text: "no"},
+					token{Text: "kyojin"},
+				},
+				Episode:  137,
+				Absolute: true,
+			},
+		},
+		{
+			input: "Frieren Ep. 05v2",
+			output: analyzeResult{
+				Tokens: tokenList{
+					token{Text: "frieren"},
+				},
+				Episode:  5,
+				Absolute: true,
+			},
+		},
+		{
+			input: "Frieren - 07v2",
+			output: analyzeResult{
+				Tokens: tokenList{
+					token{Text: "frieren"},
+				},
+				Episode:  7,
+				Absolute: true,
+			},
+		},
+		{
+			input: "Doctor.Who.S01E01-E02.Rose",
+			output: analyzeResult{
+				Tokens: tokenList{
+					token{Text: "doctor"},
+					token{Text: "who"},
+				},
+				Season:      1,
+				Episode:     1,
+				LastEpisode: 2,
+			},
+		},
+		{
+			input: "The.Daily.Show.2024.03.15.1080p.WEB",
+			output: analyzeResult{
+				Tokens: tokenList{
+					token{Text: "the"},
+					token{Text: "daily"},
+					token{Text: "show"},
+				},
+				Year:    2024,
+				Episode: -1,
+				AirDate: time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			input: "Вечерний Ургант 15.03.2024",
+			output: analyzeResult{
+				Tokens: tokenList{
+					token{Text: "вечерний"},
+					token{Text: "ургант"},
+				},
+				Year:    2024,
+				Episode: -1,
+				AirDate: time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			input: "Fargo.S02E01-03.WEB-DL",
+			output: analyzeResult{
+				Tokens: tokenList{
+					token{Text: "fargo"},
+				},
+				Season:      2,
+				Episode:     1,
+				LastEpisode: 3,
+			},
+		},
+		{
+			input: "Twin.Peaks.S01E01E02.1080p",
+			output: analyzeResult{
+				Tokens: tokenList{
+					token{Text: "twin"},
+					token{Text: "peaks"},
+				},
+				Season:      1,
+				Episode:     1,
+				LastEpisode: 2,
+			},
+		},
 	}
 
 	for i, tc := range testCases {
 		tokens := parseName(tc.input)
 		actual := analyzeFileName(tokens)
+		actual.Tags = ReleaseTags{} // теги проверяются в TestReleaseTags
 		assert.Equal(t, tc.output, actual, "Test %d failed", i)
 	}
 }
@@ -0,0 +1,78 @@
+package analysis
+
+import (
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+)
+
+const wordBound = `(^|[/._\-\s\[\]()])`
+const wordEnd = `([/._\-\s\[\]()]|$)`
+
+var (
+	sampleDirRegex   = regexp.MustCompile(`(?i)^(samples?|семплы?|сэмплы?)$`)
+	sampleStemRegex  = regexp.MustCompile(`(?i)(^(sample|семпл|сэмпл)([-._ ]|$)|[-.](sample|семпл|сэмпл)$)`)
+	trailerDirRegex  = regexp.MustCompile(`(?i)^(trailers?|teasers?|трейлеры?|тизеры?)$`)
+	trailerStemRegex = regexp.MustCompile(`(?i)(^(trailer|teaser|трейлер|тизер)[-._ ]*\d*$|-(trailer|teaser)\d*$)`)
+	extraDirRegex    = regexp.MustCompile(`(?i)^(extras?|bonus(es)?|featurettes?|behind[._ ]the[._ ]scenes|deleted[._ ]scenes|interviews|making[._ ]of|доп\.?[._ ]?материалы|бонусы?)$`)
+	extraSuffix      = regexp.MustCompile(`(?i)-(featurette|behindthescenes|deleted|interview|scene|short|extra)\.\w+$`)
+	specialDirRegex  = regexp.MustCompile(`(?i)^(specials?|спецвыпуски?|s0+|season[._ ]?0+|сезон[._ ]?0+)$`)
+	specialRegex     = regexp.MustCompile(`(?i)` + wordBound + `s00e\d+` + wordEnd)
+	soundtrackRegex  = regexp.MustCompile(`(?i)` + wordBound + `(ost|soundtracks?|саундтреки?|music)` + wordEnd)
+	coverRegex       = regexp.MustCompile(`(?i)` + wordBound + `(cover|poster|folder|fanart|front|back|covers|scans?|обложк[аи]|постер)` + wordEnd)
+)
+
+// classify refines type of the file by directory names and tokens of the path.
+// Names of films and series are often similar to the classes (Trailer Park Boys, Special Forces), so only whole
+// directory names, whole file stems and suffixes like -trailer are considered
+func classify(relpath string, layout dirLayout, result *Result) model.FileType {
+	dirs, stem := splitPath(relpath)
+	switch {
+	case layout.IsVideoFile():
+		// порядок важен: сэмпл трейлера все равно сэмпл
+		if matchAnyDir(dirs, sampleDirRegex) || sampleStemRegex.MatchString(stem) {
+			return model.FileTypeSample
+		}
+		if matchAnyDir(dirs, trailerDirRegex) || trailerStemRegex.MatchString(stem) {
+			return model.FileTypeTrailer
+		}
+		if matchAnyDir(dirs, extraDirRegex) || extraSuffix.MatchString(relpath) {
+			return model.FileTypeExtra
+		}
+		// явно указанный сезон не переопределяется
+		if result.Season == 0 && (matchAnyDir(dirs, specialDirRegex) || specialRegex.MatchString(stem)) {
+			return model.FileTypeSpecial
+		}
+	case layout.IsAudioFile():
+		if soundtrackRegex.MatchString(relpath) {
+			return model.FileTypeSoundtrack
+		}
+	case layout.IsImageFile():
+		if coverRegex.MatchString(relpath) {
+			return model.FileTypeCover
+		}
+	}
+	return result.FileType
+}
+
+// splitPath returns directories of the path and the file name without extension
+func splitPath(relpath string) (dirs []string, stem string) {
+	relpath = filepath.ToSlash(relpath)
+	if dir := path.Dir(relpath); dir != "." {
+		dirs = strings.Split(dir, "/")
+	}
+	name := path.Base(relpath)
+	return dirs, strings.TrimSpace(strings.TrimSuffix(name, path.Ext(name)))
+}
+
+func matchAnyDir(dirs []string, re *regexp.Regexp) bool {
+	for _, dir := range dirs {
+		if re.MatchString(strings.TrimSpace(dir)) {
+			return true
+		}
+	}
+	return false
+}
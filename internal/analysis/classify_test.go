@@ -0,0 +1,59 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassify(t *testing.T) {
+	type testCase struct {
+		input  string
+		output model.FileType
+	}
+
+	testCases := []testCase{
+		{input: "Dune.2021.2160p/Sample/dune.sample.mkv", output: model.FileTypeSample},
+		{input: "Dune.2021.2160p/dune-sample.mkv", output: model.FileTypeSample},
+		{input: "Dune.2021.2160p/Trailers/Trailer 1.mkv", output: model.FileTypeTrailer},
+		{input: "Dune.2021.2160p/Extras/Making Of.mkv", output: model.FileTypeExtra},
+		{input: "Dune.2021.2160p/Dune-featurette.mkv", output: model.FileTypeExtra},
+		{input: "Interview.with.the.Vampire.1994.mkv", output: model.FileTypeFilm},
+		{input: "Extras.2005/Extras.S01E01.mkv", output: model.FileTypeEpisode},
+		{input: "Doctor Who/Specials/Doctor.Who.The.Christmas.Invasion.mkv", output: model.FileTypeSpecial},
+		{input: "Doctor Who/Doctor.Who.S00E05.mkv", output: model.FileTypeSpecial},
+		{input: "Dune.2021.2160p/OST/01 - Dream of Arrakis.flac", output: model.FileTypeSoundtrack},
+		{input: "Dune.2021.2160p/cover.jpg", output: model.FileTypeCover},
+		{input: "Dune.2021.2160p/screenshot.jpg", output: model.FileTypeInsignificant},
+		{input: "Dune.2021.2160p/Dune.2021.srt", output: model.FileTypeMediaSupply},
+		{input: "Dune.2021.2160p/Dune-trailer.mkv", output: model.FileTypeTrailer},
+		{input: "Doctor Who/Season 0/Doctor.Who.The.Snowmen.mkv", output: model.FileTypeSpecial},
+
+		// названия, похожие на доп. материалы
+		{input: "Trailer Park Boys/Season 1/Trailer.Park.Boys.S01E01.mkv", output: model.FileTypeEpisode},
+		{input: "Trailer.Park.Boys.S01E01.mkv", output: model.FileTypeEpisode},
+		{input: "Special.Forces.2011.mkv", output: model.FileTypeFilm},
+		{input: "Special.Ops.Lioness.S01E01.mkv", output: model.FileTypeEpisode},
+		{input: "Show.S01E10.Special.Edition.mkv", output: model.FileTypeEpisode},
+		{input: "Specials/Show.S01E10.mkv", output: model.FileTypeEpisode},
+		{input: "The.Sample.2019.mkv", output: model.FileTypeFilm},
+		{input: "Teaser.Park.2020.mkv", output: model.FileTypeFilm},
+	}
+
+	for i, tc := range testCases {
+		assert.Equal(t, tc.output, Analyze(tc.input).FileType, "Test %d failed: %s", i, tc.input)
+	}
+
+	special := Analyze("Doctor Who/Doctor.Who.S00E05.mkv")
+	assert.Equal(t, uint(0), special.Season)
+	assert.Equal(t, 5, special.Episode)
+
+	episode := Analyze("Trailer Park Boys/Season 1/Trailer.Park.Boys.S01E01.mkv")
+	assert.Equal(t, uint(1), episode.Season)
+	assert.Equal(t, 1, episode.Episode)
+
+	film := Analyze("Special.Forces.2011.mkv")
+	assert.Equal(t, rms_library.MovieType_Film, film.BelongsTo)
+}
@@ -0,0 +1,43 @@
+package analysis
+
+import (
+	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/metainfo"
+)
+
+// RecognizeEpisodes analyzes path of the file relative to the torrent root and returns all episodes contained in the file.
+// Size of multi-episode file is distributed between its episodes
+func RecognizeEpisodes(relpath string, size uint64, n Numbering) []model.Episode {
+	result := Analyze(relpath)
+	result.ApplyNumbering(n)
+	if result.FileType != model.FileTypeEpisode || result.Season == 0 || result.Episode <= 0 {
+		return nil
+	}
+
+	first, last := result.EpisodeRange()
+	count := uint64(last - first + 1)
+	episodes := make([]model.Episode, 0, count)
+	for no := first; no <= last; no++ {
+		e := model.Episode{
+			Season:  result.Season,
+			No:      uint(no),
+			Path:    relpath,
+			Size:    size / count,
+			Quality: result.Tags.Quality,
+		}
+		if no == first {
+			e.Size += size % count
+		}
+		episodes = append(episodes, e)
+	}
+	return episodes
+}
+
+// PredictEpisodes recognizes episodes by the file list of the torrent before downloading
+func PredictEpisodes(files []metainfo.File, n Numbering) []model.Episode {
+	var episodes []model.Episode
+	for _, f := range files {
+		episodes = append(episodes, RecognizeEpisodes(f.Path, uint64(f.Size), n)...)
+	}
+	return episodes
+}
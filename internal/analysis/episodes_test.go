@@ -0,0 +1,42 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/metainfo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPredictEpisodes(t *testing.T) {
+	files := []metainfo.File{
+		{Path: "Westworld (Сезон 1-4)/Сезон 2/Westworld.S02E05.BDRip.avi", Size: 100},
+		{Path: "Westworld (Сезон 1-4)/Сезон 2/Westworld.S02E06.BDRip.avi", Size: 200},
+		{Path: "Westworld (Сезон 1-4)/cover.jpg", Size: 10},
+	}
+
+	episodes := PredictEpisodes(files, Numbering{})
+	if assert.Len(t, episodes, 2) {
+		assert.Equal(t, uint(2), episodes[0].Season)
+		assert.Equal(t, uint(5), episodes[0].No)
+		assert.Equal(t, uint64(100), episodes[0].Size)
+		assert.Equal(t, uint(6), episodes[1].No)
+	}
+
+	assert.Empty(t, PredictEpisodes(nil, Numbering{}))
+}
+
+func TestRecognizeEpisodes(t *testing.T) {
+	episodes := RecognizeEpisodes("Fargo/Season 2/Fargo.S02E01-03.mkv", 301, Numbering{})
+	if assert.Len(t, episodes, 3) {
+		for i, e := range episodes {
+			assert.Equal(t, uint(2), e.Season)
+			assert.Equal(t, uint(i+1), e.No)
+			assert.Equal(t, "Fargo/Season 2/Fargo.S02E01-03.mkv", e.Path)
+		}
+		assert.Equal(t, uint64(101), episodes[0].Size)
+		assert.Equal(t, uint64(100), episodes[2].Size)
+	}
+
+	assert.Len(t, RecognizeEpisodes("Fargo/Season 2/Fargo.S02E04.mkv", 100, Numbering{}), 1)
+	assert.Empty(t, RecognizeEpisodes("Fargo/cover.jpg", 100, Numbering{}))
+}
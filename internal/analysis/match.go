@@ -42,3 +42,26 @@ func (m orMatch) Match(t token) bool {
 func (m bracesMatch) Match(t token) bool {
 	return t.InBraces
 }
+
+// wordsMatch matches tokens by the words of the rule
+type wordsMatch struct {
+	rule tokenRule
+}
+
+// patternsMatch matches tokens by the patterns of the rule
+type patternsMatch struct {
+	rule tokenRule
+}
+
+func (m wordsMatch) Match(t token) bool {
+	return m.rule.isWord(t)
+}
+
+func (m patternsMatch) Match(t token) bool {
+	for _, p := range m.rule.patterns {
+		if p.MatchString(t.Text) {
+			return true
+		}
+	}
+	return false
+}
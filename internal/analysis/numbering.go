@@ -0,0 +1,63 @@
+package analysis
+
+import (
+	"time"
+
+	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
+)
+
+// SeasonLengths are episode counts of the series seasons, which are used to convert absolute episode numbers
+type SeasonLengths []uint
+
+// Numbering is metadata of the series, which is used to map absolute and date based episodes to the seasons
+type Numbering struct {
+	SeasonLengths SeasonLengths
+
+	// AirDates maps air date (time.DateOnly) to the season and the episode
+	AirDates map[string]model.Episode
+}
+
+// NewNumbering makes Numbering from the series metadata
+func NewNumbering(mov *model.Movie) Numbering {
+	return Numbering{
+		SeasonLengths: mov.SeasonLengths,
+		AirDates:      mov.AirDates,
+	}
+}
+
+// Map converts absolute episode number to the season and episode numbers. Without metadata all episodes belong to the first season
+func (l SeasonLengths) Map(absolute uint) (season, episode uint) {
+	season, episode = 1, absolute
+	for i, length := range l {
+		season = uint(i + 1)
+		if length == 0 || episode <= length || i == len(l)-1 {
+			return
+		}
+		episode -= length
+	}
+	return
+}
+
+// ApplyNumbering converts absolute episode number or air date of the result to the season and episode numbers
+func (r *Result) ApplyNumbering(n Numbering) {
+	if !r.AirDate.IsZero() && r.Season == 0 {
+		// без метаданных эпизод по дате не учитывается в инвентаризации, но раскладывается по году
+		if e, ok := n.AirDates[r.AirDate.Format(time.DateOnly)]; ok {
+			r.Season = e.Season
+			r.Episode = int(e.No)
+			r.BelongsTo = rms_library.MovieType_TvSeries
+		}
+		return
+	}
+	if !r.Absolute || r.Season != 0 || r.Episode <= 0 {
+		return
+	}
+	season, episode := n.SeasonLengths.Map(uint(r.Episode))
+	if r.LastEpisode > r.Episode {
+		r.LastEpisode += int(episode) - r.Episode
+	}
+	r.Season = season
+	r.Episode = int(episode)
+	r.BelongsTo = rms_library.MovieType_TvSeries
+}
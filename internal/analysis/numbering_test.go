@@ -0,0 +1,60 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeasonLengths_Map(t *testing.T) {
+	type testCase struct {
+		lengths  SeasonLengths
+		absolute uint
+		season   uint
+		episode  uint
+	}
+
+	testCases := []testCase{
+		{lengths: nil, absolute: 137, season: 1, episode: 137},
+		{lengths: SeasonLengths{25, 12, 22}, absolute: 25, season: 1, episode: 25},
+		{lengths: SeasonLengths{25, 12, 22}, absolute: 26, season: 2, episode: 1},
+		{lengths: SeasonLengths{25, 12, 22}, absolute: 40, season: 3, episode: 3},
+		{lengths: SeasonLengths{25, 12, 22}, absolute: 70, season: 3, episode: 33},
+		{lengths: SeasonLengths{25, 0, 22}, absolute: 30, season: 2, episode: 5},
+	}
+
+	for i, tc := range testCases {
+		season, episode := tc.lengths.Map(tc.absolute)
+		assert.Equal(t, tc.season, season, "Test %d failed", i)
+		assert.Equal(t, tc.episode, episode, "Test %d failed", i)
+	}
+
+	r := Analyze("[Erai-raws] Shingeki no Kyojin - 27 [1080p].mkv")
+	r.ApplyNumbering(Numbering{SeasonLengths: SeasonLengths{25, 12}})
+	assert.Equal(t, uint(2), r.Season)
+	assert.Equal(t, 2, r.Episode)
+}
+
+func TestApplyNumbering_AirDate(t *testing.T) {
+	r := Analyze("The Daily Show/The.Daily.Show.2024.03.15.1080p.mkv")
+	assert.Equal(t, model.FileTypeEpisode, r.FileType)
+	assert.Equal(t, uint(0), r.Season)
+	assert.Equal(t, "2024-03-15", r.AirDate.Format(time.DateOnly))
+
+	r.ApplyNumbering(Numbering{})
+	assert.Equal(t, uint(0), r.Season)
+	assert.Empty(t, RecognizeEpisodes("The Daily Show/The.Daily.Show.2024.03.15.1080p.mkv", 100, Numbering{}))
+
+	n := Numbering{AirDates: map[string]model.Episode{"2024-03-15": {Season: 29, No: 40}}}
+	r.ApplyNumbering(n)
+	assert.Equal(t, uint(29), r.Season)
+	assert.Equal(t, 40, r.Episode)
+
+	episodes := RecognizeEpisodes("The Daily Show/The.Daily.Show.2024.03.15.1080p.mkv", 100, n)
+	if assert.Len(t, episodes, 1) {
+		assert.Equal(t, uint(29), episodes[0].Season)
+		assert.Equal(t, uint(40), episodes[0].No)
+	}
+}
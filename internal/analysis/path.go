@@ -62,7 +62,7 @@ func (l dirLayout) IsVideoFile() bool {
 func (l dirLayout) IsSubtitlesFile() bool {
 
 	var subtitleExtensions = []string{
-		"srt", "vtt", "usf", "smil", "smi", "sami", "sub",
+		"srt", "vtt", "usf", "smil", "smi", "sami", "sub", "ass", "ssa",
 	}
 
 	ext := strings.ToLower(l.Extension)
@@ -75,6 +75,55 @@ func (l dirLayout) IsSubtitlesFile() bool {
 	return false
 }
 
+func (l dirLayout) IsAudioFile() bool {
+
+	var audioExtensions = []string{
+		"mp3", "flac", "ogg", "m4a", "aac", "wav", "ape", "wma", "opus",
+	}
+
+	ext := strings.ToLower(l.Extension)
+	for _, audioExtension := range audioExtensions {
+		if ext == audioExtension {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsAudioTrackFile checks if the file is an external audio track of the video
+func (l dirLayout) IsAudioTrackFile() bool {
+
+	var trackExtensions = []string{
+		"mka", "ac3", "eac3", "dts", "aac",
+	}
+
+	ext := strings.ToLower(l.Extension)
+	for _, trackExtension := range trackExtensions {
+		if ext == trackExtension {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (l dirLayout) IsImageFile() bool {
+
+	var imageExtensions = []string{
+		"jpg", "jpeg", "png", "webp", "bmp", "gif",
+	}
+
+	ext := strings.ToLower(l.Extension)
+	for _, imageExtension := range imageExtensions {
+		if ext == imageExtension {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (l dirLayout) IsRootBased() bool {
 	return len(l.Primary) == 0
 }
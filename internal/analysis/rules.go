@@ -0,0 +1,172 @@
+package analysis
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+//go:embed rules.json
+var defaultRulesData []byte
+
+// TokenRule matches tokens of the name by exact words or regular expressions
+type TokenRule struct {
+	Words    []string `json:"words,omitempty"`
+	Patterns []string `json:"patterns,omitempty"`
+}
+
+// Rules are data-driven rules of the file name analyzer
+type Rules struct {
+	// Season words are used for split season (сезон 1), patterns - for glued one (S01, 1season)
+	Season TokenRule `json:"season"`
+
+	// Episode words are markers of the episode number (Ep 05), patterns - episode tokens (E05, 1x05)
+	Episode TokenRule `json:"episode"`
+
+	// Tags are classes of release tags (quality, source, voice and etc), which are not a part of the title
+	Tags map[string]TokenRule `json:"tags"`
+}
+
+type tokenRule struct {
+	words    map[string]struct{}
+	patterns []*regexp.Regexp
+}
+
+type tagRule struct {
+	class string
+	rule  tokenRule
+}
+
+type compiledRules struct {
+	season  tokenRule
+	episode tokenRule
+	tags    []tagRule
+}
+
+var activeRules atomic.Pointer[compiledRules]
+
+func init() {
+	rules, err := ParseRules(defaultRulesData)
+	if err != nil {
+		panic(fmt.Sprintf("invalid embedded analyzer rules: %s", err))
+	}
+	if err = SetRules(rules); err != nil {
+		panic(fmt.Sprintf("invalid embedded analyzer rules: %s", err))
+	}
+}
+
+// DefaultRules returns rules embedded to the binary
+func DefaultRules() *Rules {
+	rules, _ := ParseRules(defaultRulesData)
+	return rules
+}
+
+// ParseRules parses rules from JSON
+func ParseRules(data []byte) (*Rules, error) {
+	rules := Rules{}
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, err
+	}
+	return &rules, nil
+}
+
+// LoadRules reads rules from the file and makes them active
+func LoadRules(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	rules, err := ParseRules(data)
+	if err != nil {
+		return fmt.Errorf("parse %s failed: %w", path, err)
+	}
+	return SetRules(rules)
+}
+
+// SetRules compiles and makes the rules active
+func SetRules(rules *Rules) error {
+	compiled, err := rules.compile()
+	if err != nil {
+		return err
+	}
+	activeRules.Store(compiled)
+	return nil
+}
+
+func getRules() *compiledRules {
+	return activeRules.Load()
+}
+
+func (r *Rules) compile() (*compiledRules, error) {
+	var err error
+	c := compiledRules{}
+	if c.season, err = r.Season.compile(); err != nil {
+		return nil, fmt.Errorf("season: %w", err)
+	}
+	if c.episode, err = r.Episode.compile(); err != nil {
+		return nil, fmt.Errorf("episode: %w", err)
+	}
+
+	classes := make([]string, 0, len(r.Tags))
+	for class := range r.Tags {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+
+	for _, class := range classes {
+		rule, err := r.Tags[class].compile()
+		if err != nil {
+			return nil, fmt.Errorf("tag '%s': %w", class, err)
+		}
+		c.tags = append(c.tags, tagRule{class: class, rule: rule})
+	}
+	return &c, nil
+}
+
+func (r TokenRule) compile() (tokenRule, error) {
+	c := tokenRule{words: map[string]struct{}{}}
+	for _, w := range r.Words {
+		c.words[strings.ToLower(w)] = struct{}{}
+	}
+	for _, p := range r.Patterns {
+		exp, err := regexp.Compile(p)
+		if err != nil {
+			return tokenRule{}, err
+		}
+		c.patterns = append(c.patterns, exp)
+	}
+	return c, nil
+}
+
+// matched returns the word or the pattern, which matches the token
+func (r tokenRule) matched(t token) (string, bool) {
+	if _, ok := r.words[t.Text]; ok {
+		return t.Text, true
+	}
+	for _, p := range r.patterns {
+		if p.MatchString(t.Text) {
+			return p.String(), true
+		}
+	}
+	return "", false
+}
+
+func (r tokenRule) isWord(t token) bool {
+	_, ok := r.words[t.Text]
+	return ok
+}
+
+// tag returns class and rule of the release tag, which matches the token
+func (c *compiledRules) tag(t token) (class string, rule string, ok bool) {
+	for _, tr := range c.tags {
+		if rule, ok = tr.rule.matched(t); ok {
+			return tr.class, rule, true
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,57 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRules(t *testing.T) {
+	defer func() { assert.NoError(t, SetRules(DefaultRules())) }()
+
+	name := "Breaking Bad Amazon S01E02 1080p.mkv"
+	assert.Equal(t, []string{"Breaking Bad Amazon"}, Analyze(name).Titles)
+
+	rules := DefaultRules()
+	rules.Tags["source"] = TokenRule{
+		Words:    append(rules.Tags["source"].Words, "Amazon"),
+		Patterns: rules.Tags["source"].Patterns,
+	}
+	assert.NoError(t, SetRules(rules))
+	assert.Equal(t, []string{"Breaking Bad"}, Analyze(name).Titles)
+
+	trace := AnalyzeTrace(name)
+	if assert.Len(t, trace.Layers, 1) {
+		var rule string
+		for _, tok := range trace.Layers[0].Tokens {
+			if tok.Text == "amazon" {
+				rule = tok.Rule
+			}
+		}
+		assert.Equal(t, "tag source: amazon", rule)
+	}
+
+	_, err := ParseRules([]byte(`{"tags": {"quality": {"patterns": ["[1080"]}}}`))
+	assert.NoError(t, err)
+	assert.Error(t, SetRules(&Rules{Tags: map[string]TokenRule{"quality": {Patterns: []string{"[1080"}}}}))
+}
+
+func TestAnalyzeTrace(t *testing.T) {
+	trace := AnalyzeTrace("Friends.S02E05.WEB-DL.1080p.rus.mkv")
+	assert.Equal(t, 5, trace.Result.Episode)
+	if !assert.Len(t, trace.Layers, 1) {
+		return
+	}
+
+	rules := map[string]string{}
+	for _, tok := range trace.Layers[0].Tokens {
+		if tok.Removed {
+			rules[tok.Text] = tok.Rule
+		}
+	}
+	assert.Equal(t, "tag quality: ^\\d\\d\\d\\d?p$", rules["1080p"])
+	assert.Equal(t, "tag voice: rus", rules["rus"])
+	assert.Contains(t, rules, "s02e05")
+	assert.Equal(t, "friends", trace.Layers[0].Tokens[0].Text)
+	assert.False(t, trace.Layers[0].Tokens[0].Removed)
+}
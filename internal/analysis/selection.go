@@ -0,0 +1,68 @@
+package analysis
+
+import (
+	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/metainfo"
+)
+
+// Wanted describes which files of the torrent are needed
+type Wanted struct {
+	// Series means only missing episodes are wanted
+	Series bool
+
+	// Have are already downloaded or expected episodes
+	Have []model.Episode
+
+	// Numbering is used to convert absolute and date based episode numbers
+	Numbering Numbering
+}
+
+// SelectFiles returns paths of the needed files of the torrent. Empty result means the whole torrent is needed
+func SelectFiles(files []metainfo.File, w Wanted) []string {
+	have := map[model.Episode]bool{}
+	for _, e := range w.Have {
+		have[model.Episode{Season: e.Season, No: e.No}] = true
+	}
+
+	// файл нужен, если в нем есть хотя бы один недостающий эпизод
+	isHave := func(result Result) bool {
+		if !w.Series || result.Season == 0 || result.Episode <= 0 {
+			return false
+		}
+		first, last := result.EpisodeRange()
+		for no := first; no <= last; no++ {
+			if !have[model.Episode{Season: result.Season, No: uint(no)}] {
+				return false
+			}
+		}
+		return true
+	}
+
+	selected := make([]string, 0, len(files))
+	significant := 0
+	for _, f := range files {
+		result := Analyze(f.Path)
+		result.ApplyNumbering(w.Numbering)
+		switch result.FileType {
+		case model.FileTypeFilm, model.FileTypeEpisode, model.FileTypeSpecial:
+			// эпизоды, которые уже есть в библиотеке, повторно не нужны
+			if isHave(result) {
+				continue
+			}
+			significant++
+		case model.FileTypeMediaSupply:
+			if isHave(result) {
+				continue
+			}
+		default:
+			continue
+		}
+		selected = append(selected, f.Path)
+	}
+
+	// если ничего полезного не нашлось или нужно все, скачиваем раздачу целиком
+	if significant == 0 || len(selected) == len(files) {
+		return nil
+	}
+	return selected
+}
@@ -0,0 +1,63 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/metainfo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectFiles(t *testing.T) {
+	series := []metainfo.File{
+		{Path: "Сезон 1/Westworld.S01E01.avi"},
+		{Path: "Сезон 1/Westworld.S01E02.avi"},
+		{Path: "Сезон 2/Westworld.S02E01.avi"},
+		{Path: "Сезон 2/Westworld.S02E01.srt"},
+		{Path: "Сезон 2/Westworld.S02E02.avi"},
+		{Path: "Extras/Making.of.avi"},
+		{Path: "cover.jpg"},
+	}
+
+	type testCase struct {
+		name   string
+		files  []metainfo.File
+		wanted Wanted
+		output []string
+	}
+
+	testCases := []testCase{
+		{
+			name:   "missing season",
+			files:  series,
+			wanted: Wanted{Series: true, Have: []model.Episode{{Season: 1, No: 1}, {Season: 1, No: 2}}},
+			output: []string{"Сезон 2/Westworld.S02E01.avi", "Сезон 2/Westworld.S02E01.srt", "Сезон 2/Westworld.S02E02.avi"},
+		},
+		{
+			name:   "skip extras",
+			files:  series,
+			wanted: Wanted{Series: true},
+			output: []string{"Сезон 1/Westworld.S01E01.avi", "Сезон 1/Westworld.S01E02.avi", "Сезон 2/Westworld.S02E01.avi", "Сезон 2/Westworld.S02E01.srt", "Сезон 2/Westworld.S02E02.avi"},
+		},
+		{
+			name:   "nothing new",
+			files:  series[:2],
+			wanted: Wanted{Series: true, Have: []model.Episode{{Season: 1, No: 1}, {Season: 1, No: 2}}},
+			output: nil,
+		},
+		{
+			name:   "film with sample",
+			files:  []metainfo.File{{Path: "Film.2020.mkv"}, {Path: "Film.2020.sample.mkv"}},
+			output: []string{"Film.2020.mkv"},
+		},
+		{
+			name:   "single file",
+			files:  []metainfo.File{{Path: "Film.2020.mkv"}},
+			output: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.output, SelectFiles(tc.files, tc.wanted), tc.name)
+	}
+}
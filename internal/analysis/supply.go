@@ -0,0 +1,115 @@
+package analysis
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/antzucaro/matchr"
+)
+
+// minSupplySimilarity is the lowest similarity of names to match supply file with one of several films
+const minSupplySimilarity = 0.5
+
+var languages = map[string][]string{
+	"rus": {"rus", "russian", "ru", "рус", "русский", "русские", "русская"},
+	"eng": {"eng", "english", "en", "англ", "английский", "английские", "английская"},
+	"ukr": {"ukr", "ukrainian", "ua", "укр", "украинский", "украинские", "украинская"},
+	"jpn": {"jpn", "japanese", "jap", "jp", "японский", "японские", "японская"},
+	"ger": {"ger", "deu", "german", "de", "немецкий", "немецкие", "немецкая"},
+	"fre": {"fre", "fra", "french", "fr", "французский", "французские", "французская"},
+	"spa": {"spa", "spanish", "es", "испанский", "испанские", "испанская"},
+	"ita": {"ita", "italian", "it", "итальянский", "итальянские", "итальянская"},
+}
+
+// MediaFile is an analyzed file of the torrent
+type MediaFile struct {
+	// Path is a path relative to the torrent root
+	Path string
+
+	Result Result
+}
+
+// detectLanguage finds language of the supply file by the file name, then by the nearest directories
+func detectLanguage(relpath string) string {
+	relpath = filepath.ToSlash(relpath)
+	stem := strings.TrimSuffix(path.Base(relpath), path.Ext(relpath))
+
+	// язык обычно указывают в конце имени файла: Movie.2010.rus.srt, Movie.2010.en.forced.srt
+	tokens := parseName(stem)
+	for i := len(tokens) - 1; i >= 0 && i >= len(tokens)-2; i-- {
+		// короткие коды (it, de) в начале имени - скорее часть названия
+		if i == 0 && len(tokens) > 1 && len([]rune(tokens[i].Text)) < 3 {
+			continue
+		}
+		if lang := languageOf(tokens[i].Text); lang != "" {
+			return lang
+		}
+	}
+
+	dirs := strings.Split(path.Dir(relpath), "/")
+	for i := len(dirs) - 1; i >= 0; i-- {
+		tokens = parseName(dirs[i])
+		for _, t := range tokens {
+			// в составных именах директорий короткие коды легко спутать со словами
+			if len(tokens) > 1 && len([]rune(t.Text)) < 3 {
+				continue
+			}
+			if lang := languageOf(t.Text); lang != "" {
+				return lang
+			}
+		}
+	}
+	return ""
+}
+
+func languageOf(word string) string {
+	for lang, words := range languages {
+		for _, w := range words {
+			if w == word {
+				return lang
+			}
+		}
+	}
+	return ""
+}
+
+// MatchSupply finds the video which subtitles or external audio track belongs to. Returns index of the video or -1
+func MatchSupply(supply MediaFile, videos []MediaFile) int {
+	s := &supply.Result
+	byEpisode := s.Episode > 0 || !s.AirDate.IsZero()
+
+	best := -1
+	bestScore := 0.
+	candidates := 0
+	for i := range videos {
+		v := &videos[i].Result
+		if byEpisode && (v.Season != s.Season || v.Episode != s.Episode || !v.AirDate.Equal(s.AirDate)) {
+			continue
+		}
+		candidates++
+
+		score := similarity(supply.Path, videos[i].Path)
+		if best < 0 || score > bestScore {
+			best = i
+			bestScore = score
+		}
+	}
+
+	// серия однозначно определяется номером, а фильм приходится угадывать по имени
+	if !byEpisode && candidates > 1 && bestScore < minSupplySimilarity {
+		return -1
+	}
+	return best
+}
+
+// similarity compares names of files without extensions, result is in range [0; 1]
+func similarity(a, b string) float64 {
+	a = strings.ToLower(strings.TrimSuffix(filepath.Base(a), filepath.Ext(a)))
+	b = strings.ToLower(strings.TrimSuffix(filepath.Base(b), filepath.Ext(b)))
+	length := max(len([]rune(a)), len([]rune(b)))
+	if length == 0 {
+		return 1
+	}
+	return 1 - float64(matchr.Levenshtein(a, b))/float64(length)
+}
@@ -0,0 +1,73 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	type testCase struct {
+		input    string
+		fileType model.FileType
+		language string
+	}
+
+	testCases := []testCase{
+		{input: "Dune.2021.2160p/Dune.2021.rus.srt", fileType: model.FileTypeMediaSupply, language: "rus"},
+		{input: "Dune.2021.2160p/Subs/English.ass", fileType: model.FileTypeMediaSupply, language: "eng"},
+		{input: "Dune.2021.2160p/Subs/Dune.2021.en.forced.ssa", fileType: model.FileTypeMediaSupply, language: "eng"},
+		{input: "Friends.S01/Rus Sound/LostFilm/Friends.S01E01.mka", fileType: model.FileTypeMediaSupply, language: "rus"},
+		{input: "Friends.S01/Звук/Украинский/Friends.S01E01.ac3", fileType: model.FileTypeMediaSupply, language: "ukr"},
+		{input: "It.2017.1080p/Subs/It.2017.srt", fileType: model.FileTypeMediaSupply, language: ""},
+		{input: "Dune.2021.2160p/Dune.2021.mkv", fileType: model.FileTypeFilm, language: ""},
+	}
+
+	for i, tc := range testCases {
+		result := Analyze(tc.input)
+		assert.Equal(t, tc.fileType, result.FileType, "Test %d failed", i)
+		assert.Equal(t, tc.language, result.Language, "Test %d failed", i)
+	}
+}
+
+func TestMatchSupply(t *testing.T) {
+	files := func(paths ...string) []MediaFile {
+		result := make([]MediaFile, 0, len(paths))
+		for _, p := range paths {
+			result = append(result, MediaFile{Path: p, Result: Analyze(p)})
+		}
+		return result
+	}
+
+	series := files(
+		"Friends.S01/Friends.S01E01.mkv",
+		"Friends.S01/Friends.S01E02.mkv",
+		"Friends.S01/Friends.S01E03.mkv",
+	)
+	films := files(
+		"Back to the Future/Back.to.the.Future.1985.mkv",
+		"Back to the Future/Back.to.the.Future.Part.II.1989.mkv",
+	)
+
+	type testCase struct {
+		supply string
+		videos []MediaFile
+		index  int
+	}
+
+	testCases := []testCase{
+		{supply: "Friends.S01/Rus Sound/Friends.S01E02.mka", videos: series, index: 1},
+		{supply: "Friends.S01/Subs/Friends.S01E03.eng.srt", videos: series, index: 2},
+		{supply: "Friends.S01/Subs/Friends.S01E07.eng.srt", videos: series, index: -1},
+		{supply: "Back to the Future/Subs/Back.to.the.Future.Part.II.1989.rus.srt", videos: films, index: 1},
+		{supply: "Back to the Future/Subs/Back.to.the.Future.1985.srt", videos: films, index: 0},
+		{supply: "Back to the Future/Subs/Commentary.srt", videos: films, index: -1},
+		{supply: "Dune/Subs/English.srt", videos: files("Dune/Dune.2021.2160p.mkv"), index: 0},
+	}
+
+	for i, tc := range testCases {
+		supply := MediaFile{Path: tc.supply, Result: Analyze(tc.supply)}
+		assert.Equal(t, tc.index, MatchSupply(supply, tc.videos), "Test %d failed", i)
+	}
+}
@@ -0,0 +1,85 @@
+package analysis
+
+import (
+	"slices"
+	"strings"
+)
+
+// ReleaseTags are metadata of the release parsed from the file name
+type ReleaseTags struct {
+	// Quality is a resolution of the video (720p, 1080p, 2160p)
+	Quality string
+
+	// Source is a release source (web-dl, bdrip, remux)
+	Source string
+
+	// Codec is a video codec (hevc, x264)
+	Codec string
+
+	// HDR is a HDR format (hdr10, dv)
+	HDR string
+
+	// Voices are voice-over tags (rus, eng, lostfilm)
+	Voices []string
+}
+
+func (t *ReleaseTags) add(class, value string) {
+	switch class {
+	case "quality":
+		if t.Quality == "" {
+			t.Quality = value
+		}
+	case "source":
+		if value == "webdl" {
+			value = "web-dl"
+		}
+		switch {
+		case t.Source == "":
+			t.Source = value
+		case t.Source == "web" && value == "dl":
+			// WEB-DL разбивается на два токена
+			t.Source = "web-dl"
+		}
+	case "codec":
+		if t.Codec == "" {
+			t.Codec = value
+		}
+	case "hdr":
+		if t.HDR == "" {
+			t.HDR = value
+		}
+	case "voice":
+		if !slices.Contains(t.Voices, value) {
+			t.Voices = append(t.Voices, value)
+		}
+	}
+}
+
+// merge fills unknown tags from other
+func (t *ReleaseTags) merge(other ReleaseTags) {
+	t.add("quality", other.Quality)
+	if t.Source == "" {
+		t.Source = other.Source
+	}
+	t.add("codec", other.Codec)
+	t.add("hdr", other.HDR)
+	for _, v := range other.Voices {
+		t.add("voice", v)
+	}
+}
+
+// IsEmpty checks if no tags were found
+func (t ReleaseTags) IsEmpty() bool {
+	return t.Quality == "" && t.Source == "" && t.Codec == "" && t.HDR == "" && len(t.Voices) == 0
+}
+
+// String returns tags in the conventional form: 1080p WEB-DL HEVC HDR10
+func (t ReleaseTags) String() string {
+	var parts []string
+	for _, p := range []string{t.Quality, strings.ToUpper(t.Source), strings.ToUpper(t.Codec), strings.ToUpper(t.HDR)} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,42 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleaseTags(t *testing.T) {
+	testCases := []struct {
+		input  string
+		output ReleaseTags
+		str    string
+	}{
+		{
+			input:  "Stranger.Things.S04.1080p.WEBDL.Rus.Eng/Stranger.Things.S04E01.mkv",
+			output: ReleaseTags{Quality: "1080p", Source: "web-dl", Voices: []string{"rus", "eng"}},
+			str:    "1080p WEB-DL",
+		},
+		{
+			input:  "Dune.Part.Two.2024.2160p.WEB-DL.HEVC.HDR10.mkv",
+			output: ReleaseTags{Quality: "2160p", Source: "web-dl", Codec: "hevc", HDR: "hdr10"},
+			str:    "2160p WEB-DL HEVC HDR10",
+		},
+		{
+			input:  "Lexx (1997) [DVDRip] x264/Lexx.S01E01.720p.mkv",
+			output: ReleaseTags{Quality: "720p", Source: "dvdrip", Codec: "x264"},
+			str:    "720p DVDRIP X264",
+		},
+		{
+			input:  "Some Movie.avi",
+			output: ReleaseTags{},
+		},
+	}
+
+	for i, tc := range testCases {
+		actual := Analyze(tc.input).Tags
+		assert.Equal(t, tc.output, actual, "Test %d failed", i)
+		assert.Equal(t, tc.str, actual.String(), "Test %d failed", i)
+		assert.Equal(t, tc.str == "", actual.IsEmpty(), "Test %d failed", i)
+	}
+}
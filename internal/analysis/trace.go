@@ -0,0 +1,65 @@
+package analysis
+
+// TraceToken describes how the analyzer handled a single token of the name
+type TraceToken struct {
+	Text     string
+	InBraces bool
+	Removed  bool
+	Rule     string
+}
+
+// TraceLayer is a token-level trace of one path component
+type TraceLayer struct {
+	Name   string
+	Input  string
+	Tokens []TraceToken
+	Title  string
+}
+
+// Trace explains the analysis of a file name
+type Trace struct {
+	Layers []TraceLayer
+	Result Result
+}
+
+// AnalyzeTrace analyzes the file name the same way as Analyze and returns token-level details
+func AnalyzeTrace(fileName string) Trace {
+	layout := extractLayout(fileName)
+	layers := []struct {
+		name  string
+		input string
+	}{
+		{"primary", layout.Primary},
+		{"secondary", layout.Secondary},
+		{"subpath", layout.SubPath},
+		{"filename", layout.FileName},
+	}
+
+	trace := Trace{Result: Analyze(fileName)}
+	for _, l := range layers {
+		if l.input == "" {
+			continue
+		}
+		result, tokens := analyzeFileNameTrace(parseName(l.input))
+		trace.Layers = append(trace.Layers, TraceLayer{
+			Name:   l.name,
+			Input:  l.input,
+			Tokens: tokens,
+			Title:  result.Tokens.String(),
+		})
+	}
+	return trace
+}
+
+func (ctx *analyzeContext) trace() []TraceToken {
+	tokens := make([]TraceToken, len(ctx.name))
+	for i, t := range ctx.name {
+		tokens[i] = TraceToken{
+			Text:     t.Text,
+			InBraces: t.InBraces,
+			Removed:  ctx.remove[i],
+			Rule:     ctx.reasons[i],
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,261 @@
+package analysis
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
+)
+
+// minTitleSimilarity is the lowest similarity of the analyzed title and the title of the movie
+const minTitleSimilarity = 0.75
+
+// maxYearDifference is an acceptable difference of years, release dates may differ between countries
+const maxYearDifference = 1
+
+var yearTokenRegex = regexp.MustCompile(`^(18|19|20)\d\d$`)
+
+// Mismatch describes why the torrent content doesn't look like the movie. Empty fields mean the check is passed or skipped
+type Mismatch struct {
+	Title string
+	Year  string
+}
+
+// IsEmpty checks the content matches the movie
+func (m Mismatch) IsEmpty() bool {
+	return m.Title == "" && m.Year == ""
+}
+
+// IsCertain checks both title and year don't match, so the content is definitely another movie
+func (m Mismatch) IsCertain() bool {
+	return m.Title != "" && m.Year != ""
+}
+
+func (m Mismatch) String() string {
+	reasons := make([]string, 0, 2)
+	for _, r := range []string{m.Title, m.Year} {
+		if r != "" {
+			reasons = append(reasons, r)
+		}
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// VerifyTitle checks the video files of the torrent contain the movie
+func VerifyTitle(mi *rms_library.MovieInfo, files []MediaFile) Mismatch {
+	expected := expectedTitles(mi)
+
+	var titles []string
+	var years []uint
+	titleChecked := false
+	titleFound := false
+	yearFound := false
+	for _, f := range files {
+		r := &f.Result
+		if r.FileType != model.FileTypeFilm && r.FileType != model.FileTypeEpisode {
+			continue
+		}
+		names := pathNames(f.Path)
+		for _, title := range r.Titles {
+			title = normalizeTitle(title)
+			// название на другом языке (без транслитерации) сравнивать не с чем
+			if !hasCommonScript(title, expected) {
+				continue
+			}
+			titles = append(titles, title)
+			titleChecked = true
+			titleFound = titleFound || matchTitle(title, expected)
+		}
+		// анализатор может принять название за год (1917, 2012), поэтому ищем название среди всех слов пути
+		for _, name := range names {
+			titleFound = titleFound || containsTitle(name, expected)
+		}
+
+		// годом может быть любое похожее на год число: 1917 (2019), Blade Runner 2049 (2017)
+		for _, year := range pathYears(names) {
+			years = append(years, year)
+			yearFound = yearFound || isYearMatched(year, mi.Year)
+		}
+	}
+
+	m := Mismatch{}
+	if titleChecked && !titleFound {
+		m.Title = fmt.Sprintf("titles %q don't match '%s'", compact(titles), mi.Title)
+	}
+
+	// у сериалов год в названии раздачи часто относится к сезону
+	if mi.Type == rms_library.MovieType_Film && mi.Year != 0 && len(years) != 0 && !yearFound {
+		m.Year = fmt.Sprintf("years %v don't match %d", compactYears(years), mi.Year)
+	}
+
+	return m
+}
+
+// expectedTitles returns normalized titles of the movie including transliteration of cyrillic ones
+func expectedTitles(mi *rms_library.MovieInfo) []string {
+	var result []string
+	for _, title := range []string{mi.Title, mi.OriginalTitle} {
+		title = normalizeTitle(title)
+		if title == "" || slices.Contains(result, title) {
+			continue
+		}
+		result = append(result, title)
+		if t := transliterate(title); t != title && !slices.Contains(result, t) {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// pathNames returns normalized names of all directories and the file name without extension
+func pathNames(relpath string) []string {
+	relpath = filepath.ToSlash(relpath)
+	parts := strings.Split(strings.TrimSuffix(relpath, path.Ext(relpath)), "/")
+	names := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if name := normalizeTitle(p); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func pathYears(names []string) []uint {
+	var years []uint
+	for _, name := range names {
+		for _, w := range strings.Fields(name) {
+			if yearTokenRegex.MatchString(w) {
+				year, _ := strconv.ParseUint(w, 10, 32)
+				years = append(years, uint(year))
+			}
+		}
+	}
+	return years
+}
+
+func normalizeTitle(title string) string {
+	tokens := parseName(title)
+	words := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		words = append(words, t.Text)
+	}
+	return strings.Join(words, " ")
+}
+
+func matchTitle(title string, expected []string) bool {
+	if title == "" {
+		return false
+	}
+	words := strings.Fields(title)
+	for _, e := range expected {
+		if similarity(title, e) >= minTitleSimilarity {
+			return true
+		}
+		// раздачи часто содержат лишние слова в названии: Dune Part One
+		if containsAll(words, strings.Fields(e)) || containsAll(strings.Fields(e), words) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsTitle checks the name contains all words of any expected title in the same order
+func containsTitle(name string, expected []string) bool {
+	name = " " + name + " "
+	for _, e := range expected {
+		if strings.Contains(name, " "+e+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAll(words, subset []string) bool {
+	for _, w := range subset {
+		if !slices.Contains(words, w) {
+			return false
+		}
+	}
+	return true
+}
+
+func isYearMatched(year uint, expected uint32) bool {
+	if expected == 0 {
+		return true
+	}
+	diff := int(year) - int(expected)
+	return diff >= -maxYearDifference && diff <= maxYearDifference
+}
+
+func compact(titles []string) []string {
+	slices.Sort(titles)
+	return slices.Compact(titles)
+}
+
+func compactYears(years []uint) []uint {
+	slices.Sort(years)
+	return slices.Compact(years)
+}
+
+// script is a set of alphabets of the string
+type script uint8
+
+const (
+	scriptLatin script = 1 << iota
+	scriptCyrillic
+)
+
+func getScript(s string) script {
+	var result script
+	for _, r := range s {
+		switch {
+		case unicode.Is(unicode.Cyrillic, r):
+			result |= scriptCyrillic
+		case unicode.Is(unicode.Latin, r):
+			result |= scriptLatin
+		}
+	}
+	return result
+}
+
+// hasCommonScript checks the title is written in the same alphabet as any of the expected titles.
+// Titles without letters (1917, 2012) are comparable with any
+func hasCommonScript(title string, expected []string) bool {
+	s := getScript(title)
+	if s == 0 {
+		return true
+	}
+	for _, e := range expected {
+		if getScript(e)&s != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+var translitTable = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh", 'з': "z", 'и': "i",
+	'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t",
+	'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "",
+	'э': "e", 'ю': "yu", 'я': "ya",
+}
+
+// transliterate converts cyrillic letters of the lowercase string to latin ones: брат -> brat
+func transliterate(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if t, ok := translitTable[r]; ok {
+			sb.WriteString(t)
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
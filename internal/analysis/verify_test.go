@@ -0,0 +1,54 @@
+package analysis
+
+import (
+	"testing"
+
+	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyTitle(t *testing.T) {
+	dune := &rms_library.MovieInfo{Title: "Дюна", OriginalTitle: "Dune", Year: 2021, Type: rms_library.MovieType_Film}
+	friends := &rms_library.MovieInfo{Title: "Друзья", OriginalTitle: "Friends", Year: 1994, Type: rms_library.MovieType_TvSeries}
+	war := &rms_library.MovieInfo{Title: "1917", OriginalTitle: "1917", Year: 2019, Type: rms_library.MovieType_Film}
+	apocalypse := &rms_library.MovieInfo{Title: "2012", OriginalTitle: "2012", Year: 2009, Type: rms_library.MovieType_Film}
+	bladeRunner := &rms_library.MovieInfo{Title: "Бегущий по лезвию 2049", OriginalTitle: "Blade Runner 2049", Year: 2017, Type: rms_library.MovieType_Film}
+	brat := &rms_library.MovieInfo{Title: "Брат", Year: 1997, Type: rms_library.MovieType_Film}
+	amelie := &rms_library.MovieInfo{Title: "Амели", OriginalTitle: "Le fabuleux destin d'Amélie Poulain", Year: 2001, Type: rms_library.MovieType_Film}
+
+	type testCase struct {
+		mi       *rms_library.MovieInfo
+		files    []string
+		mismatch bool
+		certain  bool
+	}
+
+	testCases := []testCase{
+		{mi: dune, files: []string{"Dune.2021.2160p.WEB-DL/Dune.2021.2160p.WEB-DL.mkv"}},
+		{mi: dune, files: []string{"Дюна (2021) BDRip 1080p/Дюна.2021.BDRip.1080p.mkv"}},
+		{mi: dune, files: []string{"Dune.1984.Extended.1080p/Dune.1984.mkv"}, mismatch: true},
+		{mi: dune, files: []string{"The.Matrix.1999.1080p/The.Matrix.1999.mkv"}, mismatch: true, certain: true},
+		{mi: dune, files: []string{"Dune.2021.1080p/Sample/sample.mkv", "Dune.2021.1080p/Dune.2021.mkv"}},
+		{mi: dune, files: []string{"Dune.2021.1080p/readme.txt"}},
+		{mi: friends, files: []string{"Friends.S05.2019.1080p/Friends.S05E01.mkv"}},
+		{mi: friends, files: []string{"Seinfeld.S05.1080p/Seinfeld.S05E01.mkv"}, mismatch: true},
+		{mi: war, files: []string{"1917.2019.1080p.BluRay/1917.2019.1080p.BluRay.mkv"}},
+		{mi: apocalypse, files: []string{"2012.2009.BDRip/2012.2009.BDRip.mkv"}},
+		{mi: bladeRunner, files: []string{"Blade.Runner.2049.2017.2160p/Blade.Runner.2049.2017.2160p.mkv"}},
+		{mi: bladeRunner, files: []string{"Blade Runner 2049 (2017)/Blade Runner 2049 (2017).mkv"}},
+		{mi: brat, files: []string{"Brat.1997.DVDRip/Brat.1997.DVDRip.avi"}},
+		{mi: brat, files: []string{"Brat.2.2000.DVDRip/Brat.2.2000.DVDRip.avi"}, mismatch: true},
+		{mi: amelie, files: []string{"Амели (2001) BDRip/Амели.2001.BDRip.mkv"}},
+		{mi: amelie, files: []string{"Amelie.2001.BDRip/Amelie.2001.BDRip.mkv"}},
+	}
+
+	for i, tc := range testCases {
+		var files []MediaFile
+		for _, f := range tc.files {
+			files = append(files, MediaFile{Path: f, Result: Analyze(f)})
+		}
+		mismatch := VerifyTitle(tc.mi, files)
+		assert.Equal(t, tc.mismatch, !mismatch.IsEmpty(), "Test %d failed: %s", i, mismatch)
+		assert.Equal(t, tc.certain, mismatch.IsCertain(), "Test %d failed: %s", i, mismatch)
+	}
+}
@@ -1,6 +1,13 @@
 package config
 
-import "github.com/RacoonMediaServer/rms-packages/pkg/configuration"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/selector"
+	"github.com/RacoonMediaServer/rms-packages/pkg/configuration"
+	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
+)
 
 // Remote is settings for connection to rms-bot-server service
 type Remote struct {
@@ -23,6 +30,136 @@ type Configuration struct {
 
 	// Remote is settings to connect to the Remote Server
 	Remote Remote
+
+	// Upgrade is settings of automatic quality upgrade of downloaded films
+	Upgrade Upgrade
+
+	// Rules are global rules of torrents selection
+	Rules selector.Rules
+
+	// Ranking is settings of torrents ranking
+	Ranking Ranking
+
+	// Audio is preferences of audio tracks and subtitles
+	Audio Audio
+
+	// Analyzer is settings of file names analyzer
+	Analyzer Analyzer
+
+	// Verification is settings of checking downloaded torrents against movie info
+	Verification Verification
+
+	// Retention is settings of the torrent files archive maintenance
+	Retention Retention
+}
+
+// Retention is settings of the torrent files archive maintenance
+type Retention struct {
+	// RefreshIntervalHours is a period of re-searching of archived candidates
+	RefreshIntervalHours uint
+
+	// CleanupIntervalHours is a period of removal of orphaned files
+	CleanupIntervalHours uint
+}
+
+// Verification is settings of checking downloaded torrents against movie info
+type Verification struct {
+	// RemoveMismatched means torrents which match neither title nor year of the movie are removed and blocked.
+	// Otherwise the user is only notified
+	RemoveMismatched bool
+}
+
+// Analyzer is settings of file names analyzer
+type Analyzer struct {
+	// Rules is a path to the token rules file, embedded rules are used if empty
+	Rules string
+}
+
+// Audio is preferences of audio tracks and subtitles
+type Audio struct {
+	// Languages is an ordered list of preferred audio languages (rus, eng, original, ...)
+	Languages []string
+
+	// Voices is an ordered list of preferred voice-over studios, each one is a list of name variants
+	Voices [][]string
+
+	// RequireOriginal means releases without original audio track are undesirable
+	RequireOriginal bool
+
+	// Subtitles is an ordered list of preferred subtitles languages
+	Subtitles []string
+}
+
+// Ranking is settings of torrents ranking
+type Ranking struct {
+	// Pipelines override rank functions and weights per criteria (quality, fastest, compact)
+	Pipelines map[string]selector.Pipeline
+
+	// Codecs is a list of preferred video codecs
+	Codecs []string
+
+	// Trackers are reputations of trackers
+	Trackers []selector.TrackerReputation
+
+	// Lists maps list name (favourites, watchlist, archive) to selection criteria name
+	Lists map[string]string
+
+	// MinQuality is the lowest acceptable quality for the smallest criteria
+	MinQuality string
+}
+
+// GetPipelines parses and validates ranking pipelines
+func (r Ranking) GetPipelines() (map[selector.Criteria]selector.Pipeline, error) {
+	return selector.ParsePipelines(r.Pipelines, nil)
+}
+
+// Validate checks the pipelines and the criteria of lists
+func (r Ranking) Validate() error {
+	if _, err := r.GetPipelines(); err != nil {
+		return fmt.Errorf("invalid pipelines: %w", err)
+	}
+	if _, err := r.GetListCriteria(); err != nil {
+		return fmt.Errorf("invalid criteria of lists: %w", err)
+	}
+	return nil
+}
+
+// GetListCriteria parses selection criteria of lists
+func (r Ranking) GetListCriteria() (map[rms_library.List]selector.Criteria, error) {
+	result := make(map[rms_library.List]selector.Criteria, len(r.Lists))
+	for name, criteriaName := range r.Lists {
+		list, ok := parseList(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown list: %s", name)
+		}
+		criteria, err := selector.ParseCriteria(criteriaName)
+		if err != nil {
+			return nil, err
+		}
+		result[list] = criteria
+	}
+	return result, nil
+}
+
+func parseList(name string) (rms_library.List, bool) {
+	for val, listName := range rms_library.List_name {
+		if strings.EqualFold(listName, name) {
+			return rms_library.List(val), true
+		}
+	}
+	return 0, false
+}
+
+// Upgrade is settings of periodic search of better releases for Favourites
+type Upgrade struct {
+	// Enabled turns on the upgrade subsystem
+	Enabled bool
+
+	// IntervalHours is a period of searching better releases
+	IntervalHours uint
+
+	// Threshold is a minimal rank gain of the found release for replacing the current one
+	Threshold float32
 }
 
 type Directories struct {
@@ -31,6 +168,9 @@ type Directories struct {
 
 	// Path to directory for store archive torrent files
 	Archive string
+
+	// Backup is a path to directory for exported backups of the library
+	Backup string
 }
 
 var config Configuration
@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/selector"
 	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -93,3 +94,56 @@ func (d Database) UpdateContent(ctx context.Context, id model.ID, torrents []mod
 
 	return nil
 }
+
+func (d Database) UpdateListItemRules(ctx context.Context, id model.ID, rules selector.Rules) error {
+	ctx, cancel := context.WithTimeout(ctx, databaseTimeout)
+	defer cancel()
+
+	filter := bson.D{{Key: "_id", Value: id.String()}}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "rules", Value: rules}}}}
+	_, err := d.media.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (d Database) BlockTorrent(ctx context.Context, id model.ID, link string) error {
+	ctx, cancel := context.WithTimeout(ctx, databaseTimeout)
+	defer cancel()
+
+	filter := bson.D{{Key: "_id", Value: id.String()}}
+	update := bson.D{{Key: "$addToSet", Value: bson.D{{Key: "blocked", Value: link}}}}
+	_, err := d.media.UpdateOne(ctx, filter, update)
+	return err
+}
+
+// BlockTorrentHash prevents automatic downloading of the torrent with the infohash to the item
+func (d Database) BlockTorrentHash(ctx context.Context, id model.ID, infoHash string) error {
+	ctx, cancel := context.WithTimeout(ctx, databaseTimeout)
+	defer cancel()
+
+	filter := bson.D{{Key: "_id", Value: id.String()}}
+	update := bson.D{{Key: "$addToSet", Value: bson.D{{Key: "blockedhashes", Value: infoHash}}}}
+	_, err := d.media.UpdateOne(ctx, filter, update)
+	return err
+}
+
+// FindTorrentOwner returns the item, which has the torrent with the infohash
+func (d Database) FindTorrentOwner(ctx context.Context, infoHash string) (*model.ListItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, databaseTimeout)
+	defer cancel()
+
+	result := d.media.FindOne(ctx, bson.D{{Key: "torrents.infohash", Value: infoHash}})
+	if errors.Is(result.Err(), mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+
+	item := model.ListItem{}
+	if err := result.Decode(&item); err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
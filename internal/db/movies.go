@@ -140,3 +140,33 @@ func (d Database) UpdateMovieInfoSeasons(ctx context.Context, mov *model.Movie)
 
 	return nil
 }
+
+func (d Database) UpdateMovieEpisodes(ctx context.Context, mov *model.Movie) error {
+	ctx, cancel := context.WithTimeout(ctx, databaseTimeout)
+	defer cancel()
+
+	filter := bson.D{{Key: "_id", Value: mov.ID.String()}, {Key: "contenttype", Value: int(rms_library.ContentType_TypeMovies)}}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "episodes", Value: mov.Episodes}}}}
+	_, err := d.media.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (d Database) UpdateMovieNumbering(ctx context.Context, mov *model.Movie) error {
+	ctx, cancel := context.WithTimeout(ctx, databaseTimeout)
+	defer cancel()
+
+	filter := bson.D{{Key: "_id", Value: mov.ID.String()}, {Key: "contenttype", Value: int(rms_library.ContentType_TypeMovies)}}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "seasonlengths", Value: mov.SeasonLengths}, {Key: "airdates", Value: mov.AirDates}}}}
+	_, err := d.media.UpdateOne(ctx, filter, update)
+	return err
+}
+
+// ReplaceMovie overwrites the whole movie record or creates it
+func (d Database) ReplaceMovie(ctx context.Context, mov *model.Movie) error {
+	ctx, cancel := context.WithTimeout(ctx, databaseTimeout)
+	defer cancel()
+
+	filter := bson.D{{Key: "_id", Value: mov.ID.String()}}
+	_, err := d.media.ReplaceOne(ctx, filter, mov, options.Replace().SetUpsert(true))
+	return err
+}
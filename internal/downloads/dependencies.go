@@ -11,9 +11,10 @@ type Database interface {
 	UpdateContent(ctx context.Context, id model.ID, torrents []model.TorrentRecord) error
 	SearchMovies(ctx context.Context, movieType *rms_library.MovieType) ([]*model.Movie, error)
 	GetMovie(ctx context.Context, id model.ID) (*model.Movie, error)
+	FindTorrentOwner(ctx context.Context, infoHash string) (*model.ListItem, error)
 }
 
 type DirectoryManager interface {
-	MoviesMountTorrent(mi *rms_library.MovieInfo, t *model.TorrentRecord) error
-	MoviesUmountTorrent(mi *rms_library.MovieInfo, t *model.TorrentRecord)
+	MoviesMountTorrent(mov *model.Movie, t *model.TorrentRecord) error
+	MoviesUmountTorrent(mov *model.Movie, t *model.TorrentRecord)
 }
@@ -7,7 +7,6 @@ import (
 
 	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
 	"github.com/RacoonMediaServer/rms-packages/pkg/events"
-	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
 	rms_torrent "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-torrent"
 	"go-micro.dev/v4/logger"
 )
@@ -51,15 +50,8 @@ func (m *Manager) processEvents() {
 	}
 }
 
-func (m *Manager) getMovieInfo(id model.ID) (*rms_library.MovieInfo, error) {
-	m.mu.Lock()
-	mi, ok := m.movInfo[id]
-	if ok {
-		m.mu.Unlock()
-		return mi, nil
-	}
-	m.mu.Unlock()
-
+// getMovie loads the actual state of the movie, because info, rules and numbering can be changed by other services
+func (m *Manager) getMovie(id model.ID) (*model.Movie, error) {
 	mov, err := m.db.GetMovie(context.Background(), id)
 	if err != nil {
 		return nil, err
@@ -69,16 +61,11 @@ func (m *Manager) getMovieInfo(id model.ID) (*rms_library.MovieInfo, error) {
 		return nil, errors.New("not found")
 	}
 
-	m.mu.Lock()
-	m.movInfo[id] = &mov.Info
-	m.mu.Unlock()
-
-	return &mov.Info, nil
+	return mov, nil
 }
 
 func (m *Manager) processEventNew(e *eventNew) {
 	m.mu.Lock()
-	m.movInfo[e.movie.ID] = &e.movie.Info
 	for _, t := range e.movie.Torrents {
 		m.mapTorrentToMedia[t.ID] = e.movie.ID
 	}
@@ -86,7 +73,7 @@ func (m *Manager) processEventNew(e *eventNew) {
 
 	retry := []model.TorrentRecord{}
 	for _, t := range e.movie.Torrents {
-		if err := m.layoutAddTorrent(&e.movie.Info, &t); err != nil {
+		if err := m.layoutAddTorrent(e.movie, &t); err != nil {
 			retry = append(retry, t)
 		}
 	}
@@ -98,7 +85,7 @@ func (m *Manager) processEventNew(e *eventNew) {
 }
 
 func (m *Manager) processEventAdd(e *eventAdd) {
-	mi, err := m.getMovieInfo(e.id)
+	mov, err := m.getMovie(e.id)
 	if err != nil {
 		logger.Errorf("Create layout for new torrents failed [ %s ]: %s", e.id, err)
 		return
@@ -115,7 +102,7 @@ func (m *Manager) processEventAdd(e *eventAdd) {
 
 	retry := []model.TorrentRecord{}
 	for _, t := range e.torrents {
-		if err := m.layoutAddTorrent(mi, &t); err != nil {
+		if err := m.layoutAddTorrent(mov, &t); err != nil {
 			retry = append(retry, t)
 		}
 	}
@@ -127,31 +114,31 @@ func (m *Manager) processEventAdd(e *eventAdd) {
 }
 
 func (m *Manager) processEventUpdate(e *eventUpdate) {
-	mi, err := m.getMovieInfo(e.id)
+	mov, err := m.getMovie(e.id)
 	if err != nil {
 		logger.Errorf("Update layout failed [ %s ]: %s", e.id, err)
 		return
 	}
 
 	for _, t := range e.torrents {
-		m.layoutRemoveTorrent(mi, &t)
-		m.layoutAddTorrent(mi, &t)
+		m.layoutRemoveTorrent(mov, &t)
+		m.layoutAddTorrent(mov, &t)
 	}
 }
 
 func (m *Manager) processEventRemove(e *eventRemove) {
-	mi, err := m.getMovieInfo(e.id)
+	mov, err := m.getMovie(e.id)
 	if err != nil {
 		logger.Errorf("Remove layout for new torrents failed [ %s ]: %s", e.id, err)
 		return
 	}
 
 	for _, t := range e.torrents {
-		m.layoutRemoveTorrent(mi, &t)
+		m.layoutRemoveTorrent(mov, &t)
 	}
 }
 
-func (m *Manager) layoutAddTorrent(mi *rms_library.MovieInfo, t *model.TorrentRecord) error {
+func (m *Manager) layoutAddTorrent(mov *model.Movie, t *model.TorrentRecord) error {
 	if !t.Online {
 		info, err := m.cli.GetTorrentInfo(context.Background(), &rms_torrent.GetTorrentInfoRequest{Id: t.ID})
 		if err == nil {
@@ -163,11 +150,11 @@ func (m *Manager) layoutAddTorrent(mi *rms_library.MovieInfo, t *model.TorrentRe
 		}
 	}
 
-	return m.dm.MoviesMountTorrent(mi, t)
+	return m.dm.MoviesMountTorrent(mov, t)
 }
 
-func (m *Manager) layoutRemoveTorrent(mi *rms_library.MovieInfo, t *model.TorrentRecord) {
-	m.dm.MoviesUmountTorrent(mi, t)
+func (m *Manager) layoutRemoveTorrent(mov *model.Movie, t *model.TorrentRecord) {
+	m.dm.MoviesUmountTorrent(mov, t)
 }
 
 func (m *Manager) handleExternalNotifications(ctx context.Context, event events.Notification) error {
@@ -1,14 +1,19 @@
 package downloads
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"sync"
+	"time"
 
 	"slices"
 
+	"github.com/RacoonMediaServer/rms-library/v3/internal/analysis"
 	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/metainfo"
 	"github.com/RacoonMediaServer/rms-packages/pkg/pubsub"
 	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
 	rms_torrent "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-torrent"
@@ -19,6 +24,12 @@ import (
 
 const eventsCapacity = 10000
 
+// ErrDuplicateTorrent means the torrent is already added to the library
+var ErrDuplicateTorrent = errors.New("torrent is already added")
+
+// ErrBlockedTorrent means the torrent was removed from the item before and must not be downloaded automatically
+var ErrBlockedTorrent = errors.New("torrent is blocked")
+
 // Manager is responsible for downloading and management torrents
 type Manager struct {
 	cli       rms_torrent.RmsTorrentService
@@ -28,7 +39,6 @@ type Manager struct {
 	eventChan chan interface{}
 
 	mu                sync.Mutex
-	movInfo           map[model.ID]*rms_library.MovieInfo
 	mapTorrentToMedia map[string]model.ID
 }
 
@@ -40,7 +50,6 @@ func NewManager(cli rms_torrent.RmsTorrentService, onlineCli rms_torrent.RmsTorr
 		db:                db,
 		dm:                dm,
 		eventChan:         make(chan interface{}, eventsCapacity),
-		movInfo:           map[model.ID]*rms_library.MovieInfo{},
 		mapTorrentToMedia: map[string]model.ID{},
 	}
 
@@ -79,8 +88,33 @@ func (m *Manager) client(onlinePlayback bool) rms_torrent.RmsTorrentService {
 }
 
 func (m *Manager) Download(ctx context.Context, item *model.ListItem, torrent []byte) error {
+	return m.DownloadRelease(ctx, item, torrent, nil, "")
+}
+
+// DownloadRelease starts downloading of the found release. If replaces is set, the torrent should supersede the old one after completion
+func (m *Manager) DownloadRelease(ctx context.Context, item *model.ListItem, torrent []byte, release *model.Release, replaces string) error {
+	mi, err := metainfo.Load(torrent)
+	if err != nil {
+		return err
+	}
+	if mi.Magnet {
+		torrent = bytes.TrimSpace(torrent)
+	}
+	// вручную добавленный торрент скачиваем, даже если он был удален ранее
+	if release != nil && slices.Contains(item.BlockedHashes, mi.InfoHash) {
+		return fmt.Errorf("%w for '%s' [ %s ]", ErrBlockedTorrent, item.Title, item.ID)
+	}
+	owner, err := m.db.FindTorrentOwner(ctx, mi.InfoHash)
+	if err != nil {
+		return fmt.Errorf("check torrent duplicates failed: %w", err)
+	}
+	if owner != nil {
+		return fmt.Errorf("%w to '%s' [ %s ]", ErrDuplicateTorrent, owner.Title, owner.ID)
+	}
+
 	cli := m.client(item.List == rms_library.List_WatchList)
 
+	// rms-torrent принимает как содержимое торрент-файла, так и магнет-ссылку
 	req := rms_torrent.DownloadRequest{
 		What:        torrent,
 		Description: item.Title,
@@ -98,10 +132,19 @@ func (m *Manager) Download(ctx context.Context, item *model.ListItem, torrent []
 		Title:    resp.Title,
 		Online:   item.List == rms_library.List_WatchList,
 		Location: resp.Location,
+		InfoHash: mi.InfoHash,
+		Replaces: replaces,
+		Release:  release,
+		AddedAt:  time.Now(),
 	}
+	wanted := m.getWanted(ctx, item, replaces)
+	// TODO: выборочное скачивание файлов заблокировано - DownloadRequest rms-torrent не поддерживает приоритеты файлов,
+	// поэтому раздача качается целиком, а выбор файлов нигде не сохраняется и влияет только на ожидаемые эпизоды
+	selected := analysis.SelectFiles(mi.Files, wanted)
+	torrentRecord.Predicted = analysis.PredictEpisodes(selectedFiles(mi.Files, selected), wanted.Numbering)
 	item.Torrents = append(item.Torrents, torrentRecord)
 
-	logger.Infof("Torrent added, id = %s, %d files", resp.Id, len(resp.Files))
+	logger.Infof("Torrent added, id = %s, %d files, %d needed", resp.Id, len(resp.Files), len(selected))
 
 	if err = m.db.UpdateContent(ctx, item.ID, item.Torrents); err != nil {
 		_, _ = cli.RemoveTorrent(ctx, &rms_torrent.RemoveTorrentRequest{Id: resp.Id})
@@ -116,6 +159,56 @@ func (m *Manager) Download(ctx context.Context, item *model.ListItem, torrent []
 	return nil
 }
 
+// getWanted collects episodes, which the item already has or expects from other torrents
+func (m *Manager) getWanted(ctx context.Context, item *model.ListItem, replaces string) analysis.Wanted {
+	w := analysis.Wanted{}
+	if item.ContentType != rms_library.ContentType_TypeMovies {
+		return w
+	}
+	mov, err := m.db.GetMovie(ctx, item.ID)
+	if err != nil || mov == nil || mov.Info.Type != rms_library.MovieType_TvSeries {
+		return w
+	}
+
+	w.Series = true
+	w.Numbering = analysis.NewNumbering(mov)
+	for _, e := range mov.Episodes {
+		if e.TorrentID != replaces {
+			w.Have = append(w.Have, e)
+		}
+	}
+	for _, t := range item.Torrents {
+		if t.ID != replaces {
+			w.Have = append(w.Have, t.Predicted...)
+		}
+	}
+	return w
+}
+
+// selectedFiles returns the files by the selection, empty selection means all files
+func selectedFiles(files []metainfo.File, selected []string) []metainfo.File {
+	if len(selected) == 0 {
+		return files
+	}
+	result := make([]metainfo.File, 0, len(files))
+	for _, f := range files {
+		if slices.Contains(selected, filepath.ToSlash(f.Path)) {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// UpdateLayout remounts all torrents of the movie, e.g. when episodes numbering is changed. The movie must be saved already
+func (m *Manager) UpdateLayout(mov *model.Movie) {
+	if len(mov.Torrents) != 0 {
+		m.eventChan <- &eventUpdate{
+			id:       mov.ID,
+			torrents: mov.Torrents,
+		}
+	}
+}
+
 func (m *Manager) DropTorrents(ctx context.Context, id model.ID, torrents []model.TorrentRecord) {
 	for _, t := range torrents {
 		cli := m.client(t.Online)
@@ -266,3 +359,13 @@ func (m *Manager) UpdateTorrentInfo(ctx context.Context, item *model.ListItem) e
 	}
 	return err
 }
+
+// IsDownloaded checks whether downloading of the torrent is complete
+func (m *Manager) IsDownloaded(ctx context.Context, t *model.TorrentRecord) (bool, error) {
+	cli := m.client(t.Online)
+	info, err := cli.GetTorrentInfo(ctx, &rms_torrent.GetTorrentInfoRequest{Id: t.ID})
+	if err != nil {
+		return false, err
+	}
+	return info.Status == rms_torrent.Status_Done, nil
+}
@@ -0,0 +1,99 @@
+package model
+
+import "sort"
+
+// Episode describes a single episode file found in the downloaded content
+type Episode struct {
+	// Season number
+	Season uint
+
+	// Episode number within the season
+	No uint
+
+	// Path is a path to the file relative to the torrent location
+	Path string
+
+	// Size of file in bytes
+	Size uint64
+
+	// TorrentID is an ID of torrent which contains the file
+	TorrentID string
+
+	// Quality is a video quality parsed from the file name (1080p), empty if unknown
+	Quality string
+}
+
+// SeasonEpisodes returns sorted unique numbers of downloaded episodes grouped by season
+func (m *Movie) SeasonEpisodes() map[uint][]uint {
+	result := map[uint][]uint{}
+	found := map[Episode]bool{}
+	for _, e := range m.Episodes {
+		key := Episode{Season: e.Season, No: e.No}
+		if found[key] {
+			continue
+		}
+		found[key] = true
+		result[e.Season] = append(result[e.Season], e.No)
+	}
+
+	for season := range result {
+		sort.Slice(result[season], func(i, j int) bool { return result[season][i] < result[season][j] })
+	}
+
+	return result
+}
+
+// MissingEpisodes returns numbers of episodes which are absent in the season up to the last downloaded episode
+func (m *Movie) MissingEpisodes(season uint) []uint {
+	episodes := m.SeasonEpisodes()[season]
+	if len(episodes) == 0 {
+		return nil
+	}
+
+	var missing []uint
+	next := uint(1)
+	for _, no := range episodes {
+		for ; next < no; next++ {
+			missing = append(missing, next)
+		}
+		next = no + 1
+	}
+	return missing
+}
+
+// TorrentEpisodes returns all episodes which belong to the torrent
+func (m *Movie) TorrentEpisodes(torrentId string) []Episode {
+	var result []Episode
+	for _, e := range m.Episodes {
+		if e.TorrentID == torrentId {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// PendingEpisodes returns sorted unique numbers of episodes, which are predicted by torrent files but not downloaded yet, grouped by season
+func (m *Movie) PendingEpisodes() map[uint][]uint {
+	downloaded := map[Episode]bool{}
+	for _, e := range m.Episodes {
+		downloaded[Episode{Season: e.Season, No: e.No}] = true
+	}
+
+	result := map[uint][]uint{}
+	for _, t := range m.Torrents {
+		for _, e := range t.Predicted {
+			key := Episode{Season: e.Season, No: e.No}
+			if downloaded[key] {
+				continue
+			}
+			downloaded[key] = true
+			result[e.Season] = append(result[e.Season], e.No)
+		}
+	}
+
+	for season := range result {
+		sort.Slice(result[season], func(i, j int) bool { return result[season][i] < result[season][j] })
+	}
+
+	return result
+}
@@ -14,4 +14,22 @@ const (
 
 	// FileTypeMediaSupply means subtitles, audio tracks and other
 	FileTypeMediaSupply
+
+	// FileTypeSample means short preview of the video, which is useless in the library
+	FileTypeSample
+
+	// FileTypeTrailer means trailers and teasers
+	FileTypeTrailer
+
+	// FileTypeExtra means featurettes, bonuses, deleted scenes and other extra materials
+	FileTypeExtra
+
+	// FileTypeSpecial means special episodes of TV series (season 0)
+	FileTypeSpecial
+
+	// FileTypeSoundtrack means music of the movie
+	FileTypeSoundtrack
+
+	// FileTypeCover means cover art and posters
+	FileTypeCover
 )
@@ -3,6 +3,7 @@ package model
 import (
 	"time"
 
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/selector"
 	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
 )
 
@@ -27,6 +28,15 @@ type ListItem struct {
 
 	// ID of associated torrents
 	Torrents []TorrentRecord
+
+	// Rules are item specific rules of torrents selection
+	Rules selector.Rules
+
+	// Blocked contains links of torrents, which must not be downloaded anymore
+	Blocked []string
+
+	// BlockedHashes contains infohashes of removed torrents, which must not be downloaded automatically anymore
+	BlockedHashes []string
 }
 
 type TorrentRecord struct {
@@ -35,6 +45,25 @@ type TorrentRecord struct {
 	Location string
 	Size     uint64
 	Online   bool
+	InfoHash string
+
+	// Predicted are episodes recognized by the file list of the torrent before downloading
+	Predicted []Episode
+
+	// Replaces is an ID of torrent, which should be removed when this one will be downloaded
+	Replaces string
+
+	// AddedAt is a time when the torrent was added to the library
+	AddedAt time.Time
+
+	// Release contains info about search result which the torrent was downloaded from
+	Release *Release
+
+	// Verified means the downloaded content was checked against the movie info
+	Verified bool
+
+	// Mismatch describes why the content doesn't look like the movie, empty if it matches
+	Mismatch string
 }
 
 func (li *ListItem) Size() uint64 {
@@ -46,3 +75,12 @@ func (li *ListItem) Size() uint64 {
 	}
 	return result
 }
+
+// GetRules returns item specific selection rules including blocked torrents
+func (li *ListItem) GetRules() selector.Rules {
+	rules := li.Rules
+	if len(li.Blocked) != 0 {
+		rules = rules.Merge(selector.Rules{Block: []selector.Rule{{Links: li.Blocked}}})
+	}
+	return rules
+}
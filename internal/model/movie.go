@@ -10,6 +10,40 @@ type TorrentSearchResult struct {
 	Path string
 }
 
+// Release is a short info about search result which torrent was downloaded from
+type Release struct {
+	Link    string
+	Title   string
+	Quality string
+	Voice   string
+	SizeMB  uint64
+	Seeders int64
+}
+
+// NewRelease makes Release from the search result
+func NewRelease(r *models.SearchTorrentsResult) *Release {
+	if r == nil {
+		return nil
+	}
+	release := &Release{
+		Quality: r.Quality,
+		Voice:   r.Voice,
+	}
+	if r.Link != nil {
+		release.Link = *r.Link
+	}
+	if r.Title != nil {
+		release.Title = *r.Title
+	}
+	if r.Size != nil {
+		release.SizeMB = uint64(*r.Size)
+	}
+	if r.Seeders != nil {
+		release.Seeders = *r.Seeders
+	}
+	return release
+}
+
 // Movie represents info about downloaded movie
 type Movie struct {
 	ListItem `bson:",inline"`
@@ -25,6 +59,15 @@ type Movie struct {
 
 	// ArchivedSeasons contains all seasons search results
 	ArchivedSeasons map[uint][]TorrentSearchResult
+
+	// Episodes is an inventory of downloaded episodes of series
+	Episodes []Episode
+
+	// SeasonLengths are episode counts of seasons, which are used to convert absolute episode numbers (anime)
+	SeasonLengths []uint
+
+	// AirDates maps air date (YYYY-MM-DD) to the season and the episode of daily shows
+	AirDates map[string]Episode
 }
 
 func (m *Movie) SetVoice(voice string) {
@@ -57,3 +100,21 @@ func (m *Movie) GetTorrent(id string) *TorrentRecord {
 	}
 	return nil
 }
+
+// ArchivePaths returns paths of all torrent files of the movie stored in the archive
+func (m *Movie) ArchivePaths() []string {
+	var paths []string
+	for _, t := range m.ArchivedTorrents {
+		if t.Path != "" {
+			paths = append(paths, t.Path)
+		}
+	}
+	for _, season := range m.ArchivedSeasons {
+		for _, t := range season {
+			if t.Path != "" {
+				paths = append(paths, t.Path)
+			}
+		}
+	}
+	return paths
+}
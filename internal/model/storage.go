@@ -1,6 +1,10 @@
 package model
 
-import rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
+import (
+	"time"
+
+	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
+)
 
 const MoviesCategory = "rms_movies"
 const TvSeriesCategory = "rms_tv"
@@ -17,3 +21,12 @@ func GetVideoCategory(t rms_library.MovieType) string {
 	}
 	return ""
 }
+
+// ArchiveFile is a torrent file or magnet link stored in the archive
+type ArchiveFile struct {
+	// Path relative to the archive directory
+	Path string
+
+	Size    int64
+	ModTime time.Time
+}
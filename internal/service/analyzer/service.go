@@ -0,0 +1,51 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/RacoonMediaServer/rms-library/v3/internal/analysis"
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/api"
+)
+
+type Service struct {
+}
+
+// Trace implements api.AnalyzerHandler.
+func (s *Service) Trace(ctx context.Context, req *api.AnalyzerTraceRequest, resp *api.AnalyzerTraceResponse) error {
+	if req.Path == "" {
+		return errors.New("path is empty")
+	}
+
+	trace := analysis.AnalyzeTrace(req.Path)
+	for _, l := range trace.Layers {
+		layer := api.AnalyzerLayer{
+			Name:  l.Name,
+			Input: l.Input,
+			Title: l.Title,
+		}
+		for _, t := range l.Tokens {
+			layer.Tokens = append(layer.Tokens, api.AnalyzerToken{
+				Text:     t.Text,
+				InBraces: t.InBraces,
+				Removed:  t.Removed,
+				Rule:     t.Rule,
+			})
+		}
+		resp.Layers = append(resp.Layers, layer)
+	}
+
+	r := trace.Result
+	resp.Titles = r.Titles
+	resp.Year = uint32(r.Year)
+	resp.FileType = int32(r.FileType)
+	resp.Season = uint32(r.Season)
+	resp.Episode = int32(r.Episode)
+	resp.LastEpisode = int32(r.LastEpisode)
+	resp.Absolute = r.Absolute
+	if !r.AirDate.IsZero() {
+		resp.AirDate = r.AirDate.Format(time.DateOnly)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package archive
+
+import (
+	"context"
+
+	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+	"github.com/RacoonMediaServer/rms-library/v3/internal/schedule"
+	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
+)
+
+type Database interface {
+	SearchMovies(ctx context.Context, movieType *rms_library.MovieType) ([]*model.Movie, error)
+}
+
+type Storage interface {
+	ListArchiveTorrents() ([]model.ArchiveFile, error)
+	RemoveArchiveTorrent(contentPath string) error
+}
+
+type Scheduler interface {
+	Add(t *schedule.Task) bool
+}
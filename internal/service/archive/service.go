@@ -0,0 +1,198 @@
+package archive
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+	"github.com/RacoonMediaServer/rms-library/v3/internal/schedule"
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/api"
+	"go-micro.dev/v4/logger"
+)
+
+const (
+	defaultCleanupInterval = 24 * time.Hour
+
+	// orphanGracePeriod protects files, which are just stored, but not saved to the database yet
+	orphanGracePeriod = time.Hour
+
+	maxOrphansExample = 10
+)
+
+// Service is responsible for retention of the torrent files archive
+type Service struct {
+	Database  Database
+	Storage   Storage
+	Scheduler Scheduler
+
+	// CleanupInterval is a period of orphans removal, zero means default
+	CleanupInterval time.Duration
+
+	mu sync.Mutex
+}
+
+type archiveState struct {
+	files      []model.ArchiveFile
+	referenced map[string]bool
+	orphans    []model.ArchiveFile
+	missing    int
+}
+
+// Initialize starts periodic removal of orphaned files
+func (s *Service) Initialize() {
+	interval := s.CleanupInterval
+	if interval == 0 {
+		interval = defaultCleanupInterval
+	}
+
+	task := schedule.Task{
+		Group: "archive",
+		Fn: schedule.GetPeriodicWrapper(
+			logger.Fields(map[string]interface{}{"op": "archiveCleanup"}),
+			interval,
+			func(log logger.Logger, ctx context.Context) error {
+				_, err := s.cleanup(log, ctx, false)
+				return err
+			},
+		),
+	}
+	task.After(interval)
+	s.Scheduler.Add(&task)
+}
+
+func (s *Service) getState(ctx context.Context) (*archiveState, error) {
+	movies, err := s.Database.SearchMovies(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	state := archiveState{referenced: map[string]bool{}}
+	for _, mov := range movies {
+		for _, p := range mov.ArchivePaths() {
+			state.referenced[filepath.Clean(p)] = true
+		}
+	}
+
+	state.files, err = s.Storage.ListArchiveTorrents()
+	if err != nil {
+		return nil, err
+	}
+
+	stored := map[string]bool{}
+	for _, f := range state.files {
+		stored[f.Path] = true
+		if !state.referenced[f.Path] {
+			state.orphans = append(state.orphans, f)
+		}
+	}
+	for p := range state.referenced {
+		if !stored[p] {
+			state.missing++
+		}
+	}
+
+	return &state, nil
+}
+
+func (s *Service) cleanup(log logger.Logger, ctx context.Context, dryRun bool) ([]model.ArchiveFile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, err := s.getState(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []model.ArchiveFile
+	for _, f := range state.orphans {
+		if time.Since(f.ModTime) < orphanGracePeriod {
+			continue
+		}
+		if !dryRun {
+			if err = s.Storage.RemoveArchiveTorrent(f.Path); err != nil {
+				log.Logf(logger.WarnLevel, "Remove orphaned file '%s' failed: %s", f.Path, err)
+				continue
+			}
+		}
+		removed = append(removed, f)
+	}
+
+	if len(removed) != 0 && !dryRun {
+		log.Logf(logger.InfoLevel, "Removed %d orphaned files from the archive", len(removed))
+	}
+	return removed, nil
+}
+
+// RemoveUnused removes files from the archive, if they are not referenced by any item
+func (s *Service) RemoveUnused(ctx context.Context, paths []string) {
+	if len(paths) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, err := s.getState(ctx)
+	if err != nil {
+		logger.Warnf("Get archive state failed: %s", err)
+		return
+	}
+
+	for _, p := range paths {
+		if state.referenced[filepath.Clean(p)] {
+			continue
+		}
+		if err = s.Storage.RemoveArchiveTorrent(p); err != nil {
+			logger.Warnf("Remove archive file '%s' failed: %s", p, err)
+			continue
+		}
+		logger.Debugf("Archive file '%s' removed", p)
+	}
+}
+
+// Stats implements api.ArchiveHandler.
+func (s *Service) Stats(ctx context.Context, req *api.ArchiveStatsRequest, resp *api.ArchiveStatsResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, err := s.getState(ctx)
+	if err != nil {
+		logger.Errorf("Get archive state failed: %s", err)
+		return err
+	}
+
+	resp.Files = uint32(len(state.files))
+	resp.SizeMB = sizeMB(state.files)
+	resp.Orphans = uint32(len(state.orphans))
+	resp.OrphansSizeMB = sizeMB(state.orphans)
+	resp.Missing = uint32(state.missing)
+	for i := 0; i < len(state.orphans) && i < maxOrphansExample; i++ {
+		resp.OrphansExample = append(resp.OrphansExample, state.orphans[i].Path)
+	}
+	return nil
+}
+
+// Cleanup implements api.ArchiveHandler.
+func (s *Service) Cleanup(ctx context.Context, req *api.ArchiveCleanupRequest, resp *api.ArchiveCleanupResponse) error {
+	removed, err := s.cleanup(logger.DefaultLogger, ctx, req.DryRun)
+	if err != nil {
+		logger.Errorf("Cleanup archive failed: %s", err)
+		return err
+	}
+
+	for _, f := range removed {
+		resp.Removed = append(resp.Removed, f.Path)
+	}
+	resp.FreedMB = sizeMB(removed)
+	return nil
+}
+
+func sizeMB(files []model.ArchiveFile) uint64 {
+	var total int64
+	for _, f := range files {
+		total += f.Size
+	}
+	return uint64(total) / (1024 * 1024)
+}
@@ -0,0 +1,27 @@
+package backup
+
+import (
+	"context"
+
+	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
+)
+
+type Database interface {
+	SearchMovies(ctx context.Context, movieType *rms_library.MovieType) ([]*model.Movie, error)
+	GetMovie(ctx context.Context, id model.ID) (*model.Movie, error)
+	ReplaceMovie(ctx context.Context, mov *model.Movie) error
+}
+
+type Storage interface {
+	LoadArchiveTorrent(contentPath string) ([]byte, error)
+	WriteArchiveTorrent(contentPath string, content []byte) error
+}
+
+type Downloads interface {
+	RemoveTorrent(ctx context.Context, item *model.ListItem, torrentId string) error
+}
+
+type Movies interface {
+	Watch(mov *model.Movie)
+}
@@ -0,0 +1,115 @@
+package backup
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+)
+
+const (
+	formatVersion = 1
+
+	manifestName = "library.json"
+	archiveDir   = "archive/"
+
+	// maxBackupFileSize protects from the broken archives
+	maxBackupFileSize = 64 * 1024 * 1024
+)
+
+// manifest is a content of library.json, files of the archive are stored as archive/<path>
+type manifest struct {
+	Version   int
+	CreatedAt time.Time
+	Items     []*model.Movie
+}
+
+type backup struct {
+	manifest
+	files map[string][]byte
+}
+
+func writeBackup(w io.Writer, b *backup) error {
+	tw := tar.NewWriter(w)
+
+	data, err := json.MarshalIndent(&b.manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode manifest failed: %w", err)
+	}
+	if err = writeFile(tw, manifestName, data, b.CreatedAt); err != nil {
+		return err
+	}
+
+	for p, content := range b.files {
+		if err = writeFile(tw, archiveDir+path.Clean(p), content, b.CreatedAt); err != nil {
+			return err
+		}
+	}
+
+	return tw.Close()
+}
+
+func writeFile(tw *tar.Writer, name string, content []byte, modTime time.Time) error {
+	hdr := tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(content)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(&hdr); err != nil {
+		return fmt.Errorf("write header of '%s' failed: %w", name, err)
+	}
+	if _, err := tw.Write(content); err != nil {
+		return fmt.Errorf("write '%s' failed: %w", name, err)
+	}
+	return nil
+}
+
+func readBackup(r io.Reader) (*backup, error) {
+	b := backup{files: map[string][]byte{}}
+	tr := tar.NewReader(r)
+	found := false
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read archive failed: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if hdr.Size > maxBackupFileSize {
+			return nil, fmt.Errorf("file '%s' is too large", hdr.Name)
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("read '%s' failed: %w", hdr.Name, err)
+		}
+
+		switch {
+		case hdr.Name == manifestName:
+			if err = json.Unmarshal(content, &b.manifest); err != nil {
+				return nil, fmt.Errorf("decode manifest failed: %w", err)
+			}
+			found = true
+		case strings.HasPrefix(hdr.Name, archiveDir):
+			b.files[strings.TrimPrefix(hdr.Name, archiveDir)] = content
+		}
+	}
+
+	if !found {
+		return nil, errors.New("manifest not found")
+	}
+	if b.Version > formatVersion {
+		return nil, fmt.Errorf("unsupported backup version: %d", b.Version)
+	}
+	return &b, nil
+}
@@ -0,0 +1,268 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/RacoonMediaServer/rms-library/v3/internal/lock"
+	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/api"
+	"go-micro.dev/v4/logger"
+)
+
+const lockTimeout = 20 * time.Second
+
+// Service exports and imports the whole library
+type Service struct {
+	Database  Database
+	Storage   Storage
+	Downloads Downloads
+	Movies    Movies
+	Locker    lock.Locker
+
+	// Directory is a place for backups, which are exported without the path
+	Directory string
+}
+
+// Export implements api.BackupHandler.
+func (s *Service) Export(ctx context.Context, req *api.BackupExportRequest, resp *api.BackupExportResponse) error {
+	items, err := s.Database.SearchMovies(ctx, nil)
+	if err != nil {
+		logger.Errorf("Load items failed: %s", err)
+		return err
+	}
+
+	b := backup{
+		manifest: manifest{
+			Version:   formatVersion,
+			CreatedAt: time.Now(),
+			Items:     items,
+		},
+		files: map[string][]byte{},
+	}
+	for _, mov := range items {
+		for _, p := range mov.ArchivePaths() {
+			content, err := s.Storage.LoadArchiveTorrent(p)
+			if err != nil {
+				logger.Warnf("Load archived torrent '%s' of '%s' failed: %s", p, mov.Title, err)
+				continue
+			}
+			b.files[filepath.ToSlash(p)] = content
+		}
+	}
+
+	if req.Inline {
+		buf := bytes.Buffer{}
+		if err = writeBackup(&buf, &b); err != nil {
+			logger.Errorf("Make backup failed: %s", err)
+			return err
+		}
+		if buf.Len() > api.MaxInlineBackupSize {
+			return fmt.Errorf("backup is too large to return inline: %d bytes, use the file", buf.Len())
+		}
+		resp.Data = buf.Bytes()
+	} else {
+		if resp.Path, err = s.getBackupPath(req.Path); err != nil {
+			logger.Errorf("Prepare backup file failed: %s", err)
+			return err
+		}
+		if err = saveBackup(resp.Path, &b); err != nil {
+			logger.Errorf("Save backup failed: %s", err)
+			return err
+		}
+	}
+
+	resp.Items = uint32(len(items))
+	resp.Files = uint32(len(b.files))
+	logger.Infof("Library exported: %d items, %d files", resp.Items, resp.Files)
+	return nil
+}
+
+// Import implements api.BackupHandler.
+func (s *Service) Import(ctx context.Context, req *api.BackupImportRequest, resp *api.BackupImportResponse) error {
+	conflict := req.Conflict
+	if conflict == "" {
+		conflict = api.ImportSkip
+	}
+	if conflict != api.ImportSkip && conflict != api.ImportReplace && conflict != api.ImportMerge {
+		return fmt.Errorf("unknown conflict mode: %s", conflict)
+	}
+
+	b, err := s.loadBackup(req)
+	if err != nil {
+		logger.Errorf("Parse backup failed: %s", err)
+		return err
+	}
+
+	// сначала восстанавливаем файлы, чтобы элементы не ссылались на отсутствующие торренты
+	for p, content := range b.files {
+		if err = s.Storage.WriteArchiveTorrent(filepath.FromSlash(p), content); err != nil {
+			logger.Warnf("Restore archived torrent '%s' failed: %s", p, err)
+			continue
+		}
+		resp.Files++
+	}
+
+	for _, mov := range b.Items {
+		if mov == nil || mov.ID == "" {
+			continue
+		}
+		if err = s.importItem(ctx, mov, conflict, resp); err != nil {
+			logger.Errorf("Import '%s' [ %s ] failed: %s", mov.Title, mov.ID, err)
+			return err
+		}
+	}
+
+	logger.Infof("Library imported: added %d, replaced %d, merged %d, skipped %d, files %d", resp.Added, resp.Replaced, resp.Merged, resp.Skipped, resp.Files)
+	return nil
+}
+
+func (s *Service) getBackupPath(path string) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+	if s.Directory == "" {
+		return "", errors.New("backup directory is not set")
+	}
+	if err := os.MkdirAll(s.Directory, 0755); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.Directory, "library-"+time.Now().Format("20060102-150405")+".tar"), nil
+}
+
+func saveBackup(path string, b *backup) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err = writeBackup(f, b); err != nil {
+		_ = f.Close()
+		_ = os.Remove(path)
+		return err
+	}
+	return f.Close()
+}
+
+func (s *Service) loadBackup(req *api.BackupImportRequest) (*backup, error) {
+	if req.Path == "" {
+		if len(req.Data) == 0 {
+			return nil, errors.New("backup is empty")
+		}
+		if len(req.Data) > api.MaxInlineBackupSize {
+			return nil, fmt.Errorf("backup is too large to pass inline: %d bytes, use the file", len(req.Data))
+		}
+		return readBackup(bytes.NewReader(req.Data))
+	}
+
+	f, err := os.Open(req.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readBackup(f)
+}
+
+func (s *Service) importItem(ctx context.Context, mov *model.Movie, conflict string, resp *api.BackupImportResponse) error {
+	lk, err := lock.TimedLock(ctx, s.Locker, mov.ID, lockTimeout)
+	if err != nil {
+		return err
+	}
+	defer lk.Unlock()
+
+	existing, err := s.Database.GetMovie(ctx, mov.ID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case existing == nil:
+		resp.Added++
+	case conflict == api.ImportSkip:
+		resp.Skipped++
+		return nil
+	case conflict == api.ImportReplace:
+		// торренты, которых нет в импортируемой записи, иначе останутся в rms-torrent и в структуре директорий
+		if err = s.removeStaleTorrents(ctx, existing, mov); err != nil {
+			return err
+		}
+		resp.Replaced++
+	case conflict == api.ImportMerge:
+		mergeMovie(existing, mov)
+		mov = existing
+		resp.Merged++
+	}
+
+	if err = s.Database.ReplaceMovie(ctx, mov); err != nil {
+		return err
+	}
+
+	// торренты с другого сервера, которых нет в rms-torrent, будут удалены и скачаны заново наблюдателем
+	s.Movies.Watch(mov)
+	return nil
+}
+
+// removeStaleTorrents removes torrents of the existing item, which are absent in the imported one
+func (s *Service) removeStaleTorrents(ctx context.Context, existing, imported *model.Movie) error {
+	stale := make([]string, 0, len(existing.Torrents))
+	for _, t := range existing.Torrents {
+		if imported.GetTorrent(t.ID) == nil {
+			stale = append(stale, t.ID)
+		}
+	}
+	for _, id := range stale {
+		if err := s.Downloads.RemoveTorrent(ctx, &existing.ListItem, id); err != nil {
+			return fmt.Errorf("remove torrent %s failed: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// mergeMovie adds to the existing item everything it doesn't have: archive candidates, blocked torrents and preferences
+func mergeMovie(dst, src *model.Movie) {
+	for _, link := range src.Blocked {
+		if !slices.Contains(dst.Blocked, link) {
+			dst.Blocked = append(dst.Blocked, link)
+		}
+	}
+	for _, hash := range src.BlockedHashes {
+		if !slices.Contains(dst.BlockedHashes, hash) {
+			dst.BlockedHashes = append(dst.BlockedHashes, hash)
+		}
+	}
+
+	dst.ArchivedTorrents = mergeCandidates(dst.ArchivedTorrents, src.ArchivedTorrents)
+	for season, candidates := range src.ArchivedSeasons {
+		if dst.ArchivedSeasons == nil {
+			dst.ArchivedSeasons = map[uint][]model.TorrentSearchResult{}
+		}
+		dst.ArchivedSeasons[season] = mergeCandidates(dst.ArchivedSeasons[season], candidates)
+	}
+
+	if dst.Voice == "" {
+		dst.Voice = src.Voice
+	}
+	if dst.Rules.IsEmpty() {
+		dst.Rules = src.Rules
+	}
+	if len(dst.SeasonLengths) == 0 {
+		dst.SeasonLengths = src.SeasonLengths
+	}
+	if len(dst.AirDates) == 0 {
+		dst.AirDates = src.AirDates
+	}
+}
+
+func mergeCandidates(dst, src []model.TorrentSearchResult) []model.TorrentSearchResult {
+	for _, t := range src {
+		if !slices.ContainsFunc(dst, func(e model.TorrentSearchResult) bool { return e.Path == t.Path }) {
+			dst = append(dst, t)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,16 @@
+package episodes
+
+import (
+	"context"
+
+	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+)
+
+type Database interface {
+	GetMovie(ctx context.Context, id model.ID) (*model.Movie, error)
+	UpdateMovieNumbering(ctx context.Context, mov *model.Movie) error
+}
+
+type DownloadsManager interface {
+	UpdateLayout(mov *model.Movie)
+}
@@ -0,0 +1,126 @@
+package episodes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/api"
+	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
+	"go-micro.dev/v4/logger"
+)
+
+type Service struct {
+	Database  Database
+	Downloads DownloadsManager
+}
+
+func convertNumbers(numbers []uint) []uint32 {
+	result := make([]uint32, len(numbers))
+	for i := range numbers {
+		result[i] = uint32(numbers[i])
+	}
+	return result
+}
+
+// List implements api.EpisodesHandler.
+func (s *Service) List(ctx context.Context, req *api.EpisodesListRequest, resp *api.EpisodesListResponse) error {
+	mov, err := s.Database.GetMovie(ctx, model.ID(req.Id))
+	if err != nil {
+		logger.Errorf("Get item %s failed: %s", req.Id, err)
+		return err
+	}
+	if mov == nil {
+		return errors.New("item not found")
+	}
+	if mov.Info.Type != rms_library.MovieType_TvSeries {
+		return errors.New("item is not a tv series")
+	}
+
+	seasons := mov.SeasonEpisodes()
+	pending := mov.PendingEpisodes()
+	resp.Seasons = make([]*api.Season, 0, len(seasons))
+	for no, episodes := range seasons {
+		resp.Seasons = append(resp.Seasons, &api.Season{
+			No:       uint32(no),
+			Episodes: convertNumbers(episodes),
+			Missing:  convertNumbers(mov.MissingEpisodes(no)),
+			Pending:  convertNumbers(pending[no]),
+		})
+	}
+	for no, episodes := range pending {
+		if _, ok := seasons[no]; !ok {
+			resp.Seasons = append(resp.Seasons, &api.Season{
+				No:      uint32(no),
+				Pending: convertNumbers(episodes),
+			})
+		}
+	}
+	sort.Slice(resp.Seasons, func(i, j int) bool { return resp.Seasons[i].No < resp.Seasons[j].No })
+
+	resp.SeasonLengths = convertNumbers(mov.SeasonLengths)
+	if len(mov.AirDates) != 0 {
+		resp.AirDates = make(map[string]*api.Episode, len(mov.AirDates))
+		for date, e := range mov.AirDates {
+			resp.AirDates[date] = &api.Episode{Season: uint32(e.Season), No: uint32(e.No)}
+		}
+	}
+	resp.Episodes = make([]*api.Episode, 0, len(mov.Episodes))
+	for _, e := range mov.Episodes {
+		resp.Episodes = append(resp.Episodes, &api.Episode{
+			Season:  uint32(e.Season),
+			No:      uint32(e.No),
+			Path:    e.Path,
+			Size:    e.Size,
+			Torrent: e.TorrentID,
+			Quality: e.Quality,
+		})
+	}
+
+	return nil
+}
+
+// SetNumbering implements api.EpisodesHandler.
+func (s *Service) SetNumbering(ctx context.Context, req *api.EpisodesSetNumberingRequest, resp *api.EpisodesSetNumberingResponse) error {
+	mov, err := s.Database.GetMovie(ctx, model.ID(req.Id))
+	if err != nil {
+		logger.Errorf("Get item %s failed: %s", req.Id, err)
+		return err
+	}
+	if mov == nil {
+		return errors.New("item not found")
+	}
+	if mov.Info.Type != rms_library.MovieType_TvSeries {
+		return errors.New("item is not a tv series")
+	}
+
+	mov.SeasonLengths = make([]uint, len(req.SeasonLengths))
+	for i, length := range req.SeasonLengths {
+		mov.SeasonLengths[i] = uint(length)
+	}
+
+	mov.AirDates = make(map[string]model.Episode, len(req.AirDates))
+	for date, e := range req.AirDates {
+		if _, err = time.Parse(time.DateOnly, date); err != nil {
+			return fmt.Errorf("invalid air date '%s': %w", date, err)
+		}
+		if e == nil || e.Season == 0 || e.No == 0 {
+			return fmt.Errorf("invalid episode of air date '%s'", date)
+		}
+		mov.AirDates[date] = model.Episode{Season: uint(e.Season), No: uint(e.No)}
+	}
+
+	if err = s.Database.UpdateMovieNumbering(ctx, mov); err != nil {
+		logger.Errorf("Update numbering of '%s' [ %s ] failed: %s", mov.Info.Title, mov.ID, err)
+		return err
+	}
+
+	// раскладка эпизодов по сезонам зависит от нумерации
+	s.Downloads.UpdateLayout(mov)
+
+	logger.Infof("Numbering of '%s' [ %s ] updated: %v, %d air dates", mov.Info.Title, mov.ID, mov.SeasonLengths, len(mov.AirDates))
+	return nil
+}
@@ -12,6 +12,7 @@ type Database interface {
 	MoveListItem(ctx context.Context, id model.ID, newList rms_library.List) error
 	GetListItem(ctx context.Context, id model.ID) (*model.ListItem, error)
 	DeleteListItem(ctx context.Context, id model.ID) error
+	GetMovie(ctx context.Context, id model.ID) (*model.Movie, error)
 }
 
 type Movies interface {
@@ -25,3 +26,7 @@ type Scheduler interface {
 type DownloadManager interface {
 	DropTorrents(ctx context.Context, id model.ID, torrents []model.TorrentRecord)
 }
+
+type Archive interface {
+	RemoveUnused(ctx context.Context, paths []string)
+}
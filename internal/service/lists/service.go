@@ -17,6 +17,7 @@ type Service struct {
 	Movies    Movies
 	Scheduler Scheduler
 	Downloads DownloadManager
+	Archive   Archive
 	Locker    lock.Locker
 }
 
@@ -65,6 +66,14 @@ func (s *Service) Delete(ctx context.Context, req *rms_library.ListsDeleteReques
 	if item == nil {
 		return errors.New("not found")
 	}
+
+	var archived []string
+	if id.ContentType() == rms_library.ContentType_TypeMovies {
+		if mov, err := s.Database.GetMovie(ctx, id); err == nil && mov != nil {
+			archived = mov.ArchivePaths()
+		}
+	}
+
 	if err = s.Database.DeleteListItem(ctx, id); err != nil {
 		logger.Errorf("Delete '%s'from db failed: %s", req.Id, err)
 		return err
@@ -73,6 +82,9 @@ func (s *Service) Delete(ctx context.Context, req *rms_library.ListsDeleteReques
 	s.Scheduler.Cancel(req.Id)
 	s.Downloads.DropTorrents(ctx, id, item.Torrents)
 
+	// файлы архива могут быть общими у нескольких элементов, удаляем только неиспользуемые
+	s.Archive.RemoveUnused(ctx, archived)
+
 	return nil
 }
 
@@ -11,8 +11,6 @@ import (
 	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
 	"github.com/RacoonMediaServer/rms-library/v3/internal/schedule"
 	"github.com/RacoonMediaServer/rms-library/v3/pkg/movsearch"
-	"github.com/RacoonMediaServer/rms-library/v3/pkg/selector"
-	"github.com/RacoonMediaServer/rms-media-discovery/pkg/media"
 	"github.com/RacoonMediaServer/rms-packages/pkg/events"
 	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
 	"go-micro.dev/v4/logger"
@@ -122,16 +120,7 @@ func (l MoviesService) searchAndDownload(log logger.Logger, ctx context.Context,
 		strategy = &movsearch.SimpleStrategy{Engine: searchEngine, Selector: sel}
 	}
 
-	selopts := selector.Options{
-		Criteria:  selector.CriteriaQuality,
-		MediaType: media.Movies,
-		Query:     mov.Info.Title,
-	}
-
-	if mov.List == rms_library.List_WatchList {
-		selopts.Criteria = selector.CriteriaFastest
-	}
-
+	selopts := l.getSelectorOptions(mov)
 	result, err := strategy.Search(ctx, mov.ID.String(), &mov.Info, selopts)
 	if err != nil {
 		return fmt.Errorf("search content failed: %w", err)
@@ -142,7 +131,7 @@ func (l MoviesService) searchAndDownload(log logger.Logger, ctx context.Context,
 	}
 
 	for _, r := range result {
-		if err = l.dm.Download(ctx, &mov.ListItem, r.Torrent); err != nil {
+		if err = l.dm.DownloadRelease(ctx, &mov.ListItem, r.Torrent, model.NewRelease(r.Info), ""); err != nil {
 			log.Logf(logger.ErrorLevel, "Download failed: %s", err)
 		}
 	}
@@ -174,11 +163,7 @@ func (l MoviesService) restoreFromArchive(log logger.Logger, ctx context.Context
 
 func (l MoviesService) searchAndSave(log logger.Logger, ctx context.Context, mov *model.Movie) error {
 	sel := l.getMovieSelector(mov)
-	opts := selector.Options{
-		Criteria:  selector.CriteriaQuality,
-		MediaType: media.Movies,
-		Query:     mov.Info.Title,
-	}
+	opts := l.getSelectorOptions(mov)
 
 	searchEngine := movsearch.NewRemoteSearchEngine(l.cli.Torrents, l.auth)
 
@@ -191,13 +176,16 @@ func (l MoviesService) searchAndSave(log logger.Logger, ctx context.Context, mov
 		return errors.New("nothing found")
 	}
 
-	sel.Sort(result, opts)
+	result = sel.Filter(result, opts)
+	if err = sel.Sort(result, opts); err != nil {
+		logger.Errorf("Sort torrents failed: %s", err)
+		return err
+	}
 	result = boundResults(result)
 
 	mov.ArchivedTorrents = l.fetchTorrentFiles(context.Background(), searchEngine, mov.Info.Title, result)
 
 	if mov.Info.Type == rms_library.MovieType_TvSeries && mov.Info.Seasons != nil {
-		opts.Criteria = selector.CriteriaQuality
 		mov.ArchivedSeasons = map[uint][]model.TorrentSearchResult{}
 		for season := uint(1); season <= uint(*mov.Info.Seasons); season++ {
 			result, err = searchEngine.SearchTorrents(context.Background(), mov.ID.String(), &mov.Info, &season)
@@ -205,7 +193,11 @@ func (l MoviesService) searchAndSave(log logger.Logger, ctx context.Context, mov
 				logger.Errorf("Find torrents failed: %s", err)
 				continue
 			}
-			sel.Sort(result, opts)
+			result = sel.Filter(result, opts)
+			if err = sel.Sort(result, opts); err != nil {
+				logger.Errorf("Sort torrents failed: %s", err)
+				continue
+			}
 			result = boundResults(result)
 			mov.ArchivedSeasons[uint(season)] = l.fetchTorrentFiles(context.Background(), searchEngine, mov.Info.Title, result)
 			logger.Infof("For %s [ %s ] found season no%.d, torrents: %d", mov.Info.Title, mov.ID, season, len(result))
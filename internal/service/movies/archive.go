@@ -2,15 +2,23 @@ package movies
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
 
+	"github.com/RacoonMediaServer/rms-library/v3/internal/lock"
 	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
 	"github.com/RacoonMediaServer/rms-library/v3/pkg/movsearch"
 	"github.com/RacoonMediaServer/rms-media-discovery/pkg/client/models"
+	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
 	"go-micro.dev/v4/logger"
 )
 
 const maxTorrentsInWatchListItem = 5
 
+const defaultArchiveRefreshInterval = 7 * 24 * time.Hour
+
 func boundResults(results []*models.SearchTorrentsResult) []*models.SearchTorrentsResult {
 	if len(results) > maxTorrentsInWatchListItem {
 		return results[:maxTorrentsInWatchListItem]
@@ -21,22 +29,156 @@ func boundResults(results []*models.SearchTorrentsResult) []*models.SearchTorren
 func (l MoviesService) fetchTorrentFiles(ctx context.Context, searcher movsearch.SearchEngine, title string, results []*models.SearchTorrentsResult) []model.TorrentSearchResult {
 	items := make([]model.TorrentSearchResult, 0, len(results))
 	for _, r := range results {
-		content, err := searcher.GetTorrentFile(ctx, *r.Link)
+		if item, ok := l.fetchTorrentFile(ctx, searcher, title, r); ok {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+func (l MoviesService) fetchTorrentFile(ctx context.Context, searcher movsearch.SearchEngine, title string, r *models.SearchTorrentsResult) (model.TorrentSearchResult, bool) {
+	content, err := searcher.GetTorrentFile(ctx, *r.Link)
+	if err != nil {
+		logger.Warnf("Download torrent failed: %s", err)
+		return model.TorrentSearchResult{}, false
+	}
+	pathToTorrentFile, err := l.dir.StoreArchiveTorrent(title, content)
+	if err != nil {
+		logger.Warnf("Save to watchlist failed: %s", err)
+		return model.TorrentSearchResult{}, false
+	}
+	return model.TorrentSearchResult{
+		SearchTorrentsResult: *r,
+		Path:                 pathToTorrentFile,
+	}, true
+}
+
+func (l MoviesService) getArchiveRefreshInterval() time.Duration {
+	if l.retention.RefreshIntervalHours == 0 {
+		return defaultArchiveRefreshInterval
+	}
+	return time.Duration(l.retention.RefreshIntervalHours) * time.Hour
+}
+
+func (l MoviesService) asyncRefreshArchive(log logger.Logger, ctx context.Context, id model.ID) error {
+	lk, err := lock.TimedLock(ctx, l.lk, id, lockWait)
+	if err != nil {
+		return fmt.Errorf("Lock item failed: %w", err)
+	}
+	defer lk.Unlock()
+
+	mov, err := l.db.GetMovie(ctx, id)
+	if err != nil {
+		return fmt.Errorf("load movie from database failed: %w", err)
+	}
+	if mov == nil {
+		return errors.New("movie not found")
+	}
+	if mov.List != rms_library.List_Archive || mov.Info.Type == rms_library.MovieType_Clip {
+		return nil
+	}
+
+	return l.refreshArchive(log, ctx, mov)
+}
+
+// refreshArchive searches the movie again and replaces archived candidates by the better or alive ones
+func (l MoviesService) refreshArchive(log logger.Logger, ctx context.Context, mov *model.Movie) error {
+	sel := l.getMovieSelector(mov)
+	opts := l.getSelectorOptions(mov)
+	searchEngine := movsearch.NewRemoteSearchEngine(l.cli.Torrents, l.auth)
+
+	search := func(season *uint) ([]*models.SearchTorrentsResult, error) {
+		result, err := searchEngine.SearchTorrents(ctx, mov.ID.String(), &mov.Info, season)
 		if err != nil {
-			logger.Warnf("Download torrent failed: %s", err)
-			continue
+			return nil, err
+		}
+		result = sel.Filter(result, opts)
+		if err = sel.Sort(result, opts); err != nil {
+			return nil, err
+		}
+		return boundResults(result), nil
+	}
+
+	before := mov.ArchivePaths()
+
+	if len(mov.ArchivedTorrents) != 0 {
+		result, err := search(nil)
+		if err != nil {
+			return fmt.Errorf("search torrents failed: %w", err)
 		}
-		pathToTorrentFile, err := l.dir.StoreArchiveTorrent(title, content)
+		mov.ArchivedTorrents = l.refreshCandidates(ctx, searchEngine, mov.Info.Title, mov.ArchivedTorrents, result)
+	}
+
+	for season, candidates := range mov.ArchivedSeasons {
+		no := season
+		result, err := search(&no)
 		if err != nil {
-			logger.Warnf("Save to watchlist failed: %s", err)
+			log.Logf(logger.WarnLevel, "Search torrents of %d season failed: %s", season, err)
 			continue
 		}
-		item := model.TorrentSearchResult{
-			SearchTorrentsResult: *r,
-			Path:                 pathToTorrentFile,
+		mov.ArchivedSeasons[season] = l.refreshCandidates(ctx, searchEngine, mov.Info.Title, candidates, result)
+	}
+
+	if err := l.db.UpdateMovieArchiveContent(ctx, mov); err != nil {
+		return fmt.Errorf("update archive failed: %w", err)
+	}
+
+	after := mov.ArchivePaths()
+	var unused []string
+	for _, p := range before {
+		if !slices.Contains(after, p) {
+			unused = append(unused, p)
 		}
-		items = append(items, item)
 	}
+	l.archive.RemoveUnused(ctx, unused)
 
-	return items
+	log.Logf(logger.InfoLevel, "Archive refreshed, %d candidates replaced", len(unused))
+	return nil
+}
+
+// refreshCandidates makes the new list of candidates by the fresh search results. Already stored files are reused,
+// dead candidates are dropped. If nothing found, old candidates are kept, the tracker may be unavailable
+func (l MoviesService) refreshCandidates(ctx context.Context, searcher movsearch.SearchEngine, title string, old []model.TorrentSearchResult, fresh []*models.SearchTorrentsResult) []model.TorrentSearchResult {
+	if len(fresh) == 0 {
+		return old
+	}
+
+	findOld := func(link *string) *model.TorrentSearchResult {
+		for i := range old {
+			if link != nil && old[i].Link != nil && *old[i].Link == *link {
+				return &old[i]
+			}
+		}
+		return nil
+	}
+
+	result := make([]model.TorrentSearchResult, 0, maxTorrentsInWatchListItem)
+	for _, r := range fresh {
+		if stored := findOld(r.Link); stored != nil {
+			// обновляем информацию о раздаче (сиды, размер), файл уже в архиве
+			result = append(result, model.TorrentSearchResult{SearchTorrentsResult: *r, Path: stored.Path})
+			continue
+		}
+		if item, ok := l.fetchTorrentFile(ctx, searcher, title, r); ok {
+			result = append(result, item)
+		}
+	}
+
+	// если свежих раздач мало, оставляем живые старые
+	for _, t := range old {
+		if len(result) >= maxTorrentsInWatchListItem {
+			break
+		}
+		if isDeadCandidate(&t) || slices.ContainsFunc(result, func(r model.TorrentSearchResult) bool { return r.Path == t.Path }) {
+			continue
+		}
+		result = append(result, t)
+	}
+
+	return result
+}
+
+func isDeadCandidate(t *model.TorrentSearchResult) bool {
+	return t.Seeders != nil && *t.Seeders == 0
 }
@@ -3,6 +3,7 @@ package movies
 import (
 	"context"
 
+	"github.com/RacoonMediaServer/rms-library/v3/internal/analysis"
 	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
 	"github.com/RacoonMediaServer/rms-library/v3/internal/schedule"
 	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
@@ -20,15 +21,23 @@ type Database interface {
 	GetMovie(ctx context.Context, id model.ID) (*model.Movie, error)
 	UpdateMovieArchiveContent(ctx context.Context, mov *model.Movie) error
 	UpdateMovieInfoSeasons(ctx context.Context, mov *model.Movie) error
+	UpdateMovieEpisodes(ctx context.Context, mov *model.Movie) error
+	UpdateContent(ctx context.Context, id model.ID, torrents []model.TorrentRecord) error
+	FindTorrentOwner(ctx context.Context, infoHash string) (*model.ListItem, error)
+	BlockTorrent(ctx context.Context, id model.ID, link string) error
 }
 
 type DirectoryManager interface {
 	StoreArchiveTorrent(itemTitle string, torrent []byte) (path string, err error)
 	LoadArchiveTorrent(contentPath string) ([]byte, error)
+	MoviesScanEpisodes(t *model.TorrentRecord, numbering analysis.Numbering) ([]model.Episode, error)
+	MoviesAnalyzeFiles(t *model.TorrentRecord) ([]analysis.MediaFile, error)
 }
 
 type DownloadsManager interface {
 	Download(ctx context.Context, item *model.ListItem, torrent []byte) error
+	DownloadRelease(ctx context.Context, item *model.ListItem, torrent []byte, release *model.Release, replaces string) error
+	IsDownloaded(ctx context.Context, t *model.TorrentRecord) (bool, error)
 	RemoveTorrent(ctx context.Context, item *model.ListItem, torrentId string) error
 	DropMissedTorrents(ctx context.Context, item *model.ListItem) error
 	UpdateTorrentInfo(ctx context.Context, item *model.ListItem) error
@@ -38,3 +47,7 @@ type Scheduler interface {
 	Add(t *schedule.Task) bool
 	Cancel(groupId string)
 }
+
+type Archive interface {
+	RemoveUnused(ctx context.Context, paths []string)
+}
@@ -0,0 +1,266 @@
+package movies
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/movsearch"
+	"github.com/RacoonMediaServer/rms-media-discovery/pkg/client/models"
+	"github.com/RacoonMediaServer/rms-packages/pkg/events"
+	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
+	"go-micro.dev/v4/logger"
+)
+
+func (l MoviesService) checkNewSeasons(log logger.Logger, ctx context.Context, mov *model.Movie, totalSeasons uint) error {
+	newSeasonsCount := totalSeasons - uint(*mov.Info.Seasons)
+	log.Logf(logger.InfoLevel, "Found info about %d new seasons", newSeasonsCount)
+
+	sel := l.getMovieSelector(mov)
+	opts := l.getSelectorOptions(mov)
+
+	searchEngine := movsearch.NewRemoteSearchEngine(l.cli.Torrents, l.auth)
+
+	foundRealeses := []uint32{}
+	for no := uint(*mov.Info.Seasons); no < totalSeasons; no++ {
+		result, err := searchEngine.SearchTorrents(ctx, mov.ID.String(), &mov.Info, &no)
+		if err != nil {
+			log.Logf(logger.WarnLevel, "Find torrents for %d season failed: %s", no, err)
+			break
+		}
+		if len(result) == 0 {
+			break
+		}
+
+		log.Logf(logger.InfoLevel, "Found new releases of %d season!", no)
+
+		if mov.List != rms_library.List_Archive {
+			selected, err := sel.Select(result, opts)
+			if err != nil {
+				log.Logf(logger.WarnLevel, "Select torrent of %d season failed: %s", no, err)
+				break
+			}
+			torrentFile, err := searchEngine.GetTorrentFile(ctx, *selected.Link)
+			if err != nil {
+				log.Logf(logger.WarnLevel, "Get torrent file of %d season failed: %s", no, err)
+				break
+			}
+			if err := l.dm.DownloadRelease(ctx, &mov.ListItem, torrentFile, model.NewRelease(selected), ""); err != nil {
+				log.Logf(logger.WarnLevel, "Download new season failed: %s", err)
+				break
+			}
+		} else {
+			result = sel.Filter(result, opts)
+			if err := sel.Sort(result, opts); err != nil {
+				log.Logf(logger.WarnLevel, "Sort torrents of %d season failed: %s", no, err)
+				break
+			}
+			result = boundResults(result)
+			mov.ArchivedSeasons[uint(no)] = l.fetchTorrentFiles(ctx, searchEngine, mov.Info.Title, result)
+			if err := l.db.UpdateMovieArchiveContent(ctx, mov); err != nil {
+				log.Logf(logger.WarnLevel, "update archive failed: %s", err)
+				break
+			}
+		}
+		foundRealeses = append(foundRealeses, uint32(no))
+	}
+
+	if len(foundRealeses) == 0 {
+		return nil
+	}
+
+	*mov.Info.Seasons += uint32(len(foundRealeses))
+	if err := l.db.UpdateMovieInfoSeasons(ctx, mov); err != nil {
+		return fmt.Errorf("update seasons count in database failed: %s", err)
+	}
+
+	log.Logf(logger.InfoLevel, "New releases %+v added!", foundRealeses)
+	l.notifyUser(log, ctx, mov, events.Notification_NewContentReleased, foundRealeses)
+	return nil
+}
+
+// hasPendingReplacements checks whether some torrents are downloading to supersede the old ones
+func hasPendingReplacements(mov *model.Movie) bool {
+	for _, t := range mov.Torrents {
+		if t.Replaces != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// getSeasonTorrent returns ID of the torrent which contains most episodes of the season
+func getSeasonTorrent(mov *model.Movie, season uint) string {
+	counts := map[string]int{}
+	best := ""
+	for _, e := range mov.Episodes {
+		if e.Season != season {
+			continue
+		}
+		counts[e.TorrentID]++
+		if counts[e.TorrentID] > counts[best] {
+			best = e.TorrentID
+		}
+	}
+	return best
+}
+
+// filterMoreCompleteReleases leaves only releases which contain episodes after the last downloaded one
+func filterMoreCompleteReleases(list []*models.SearchTorrentsResult, lastEpisode uint, hasGaps bool) []*models.SearchTorrentsResult {
+	result := make([]*models.SearchTorrentsResult, 0, len(list))
+	for _, r := range list {
+		if r.Title == nil {
+			continue
+		}
+		info, ok := movsearch.ParseEpisodes(*r.Title)
+		if !ok {
+			// о составе раздачи ничего не известно, имеет смысл только при наличии пропусков
+			if hasGaps {
+				result = append(result, r)
+			}
+			continue
+		}
+		if info.From <= 1 && (info.To > lastEpisode || (hasGaps && info.To >= lastEpisode)) {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// getSeasonLength returns count of episodes of the season by metadata, zero if unknown
+func getSeasonLength(mov *model.Movie, season uint) uint {
+	if season == 0 || season > uint(len(mov.SeasonLengths)) {
+		return 0
+	}
+	return mov.SeasonLengths[season-1]
+}
+
+// getMissingEpisodes returns episodes of the season, which are neither downloaded nor pending.
+// Episodes after the last downloaded one are known only if the season length is presented
+func getMissingEpisodes(mov *model.Movie, season uint) []uint {
+	length := getSeasonLength(mov, season)
+	if length == 0 {
+		return mov.MissingEpisodes(season)
+	}
+
+	present := map[uint]bool{}
+	for _, no := range mov.SeasonEpisodes()[season] {
+		present[no] = true
+	}
+	for _, no := range mov.PendingEpisodes()[season] {
+		present[no] = true
+	}
+
+	var missing []uint
+	for no := uint(1); no <= length; no++ {
+		if !present[no] {
+			missing = append(missing, no)
+		}
+	}
+	return missing
+}
+
+// filterCompleteReleases leaves only releases which contain the season from the start up to the episode
+func filterCompleteReleases(list []*models.SearchTorrentsResult, upTo uint) []*models.SearchTorrentsResult {
+	result := make([]*models.SearchTorrentsResult, 0, len(list))
+	for _, r := range list {
+		if r.Title == nil {
+			continue
+		}
+		info, ok := movsearch.ParseEpisodes(*r.Title)
+		if !ok || (info.From <= 1 && info.To >= upTo) {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+func (l MoviesService) checkOngoingSeason(log logger.Logger, ctx context.Context, mov *model.Movie) {
+	// ищем более полные раздачи только для текущего (выходящего) сезона
+	season := uint(*mov.Info.Seasons)
+	episodes := mov.SeasonEpisodes()[season]
+	if len(episodes) == 0 {
+		return
+	}
+	if hasPendingReplacements(mov) {
+		log.Logf(logger.DebugLevel, "Some torrents are replacing now, skip searching")
+		return
+	}
+
+	lastEpisode := episodes[len(episodes)-1]
+	missing := getMissingEpisodes(mov, season)
+	length := getSeasonLength(mov, season)
+	if length != 0 && len(missing) == 0 {
+		log.Logf(logger.DebugLevel, "Season %d is complete", season)
+		return
+	}
+	if len(missing) != 0 {
+		log.Logf(logger.InfoLevel, "Season %d misses episodes %+v", season, missing)
+	}
+
+	searchEngine := movsearch.NewRemoteSearchEngine(l.cli.Torrents, l.auth)
+	result, err := searchEngine.SearchTorrents(ctx, mov.ID.String(), &mov.Info, &season)
+	if err != nil {
+		log.Logf(logger.WarnLevel, "Find torrents for %d season failed: %s", season, err)
+		return
+	}
+
+	if length != 0 {
+		result = filterCompleteReleases(result, missing[len(missing)-1])
+	} else {
+		result = filterMoreCompleteReleases(result, lastEpisode, len(missing) != 0)
+	}
+	if len(result) == 0 {
+		log.Logf(logger.DebugLevel, "More complete releases of %d season not found", season)
+		return
+	}
+
+	selected, err := l.getMovieSelector(mov).Select(result, l.getSelectorOptions(mov))
+	if err != nil {
+		log.Logf(logger.DebugLevel, "Select release of %d season failed: %s", season, err)
+		return
+	}
+	torrentFile, err := searchEngine.GetTorrentFile(ctx, *selected.Link)
+	if err != nil {
+		log.Logf(logger.WarnLevel, "Get torrent file of %d season failed: %s", season, err)
+		return
+	}
+
+	if err = l.dm.DownloadRelease(ctx, &mov.ListItem, torrentFile, model.NewRelease(selected), getSeasonTorrent(mov, season)); err != nil {
+		log.Logf(logger.WarnLevel, "Download more complete release of %d season failed: %s", season, err)
+		return
+	}
+	log.Logf(logger.InfoLevel, "More complete release of %d season found: %s", season, *selected.Title)
+}
+
+func countSeasonEpisodes(episodes []model.Episode) map[uint]int {
+	result := map[uint]int{}
+	found := map[model.Episode]bool{}
+	for _, e := range episodes {
+		key := model.Episode{Season: e.Season, No: e.No}
+		if !found[key] {
+			found[key] = true
+			result[e.Season]++
+		}
+	}
+	return result
+}
+
+// isBetterReplacement checks that the new torrent contains not less episodes of each season of the old one.
+// Films replacements are selected by rank before downloading, so they are always better
+func isBetterReplacement(mov *model.Movie, newTorrent, oldTorrent string) bool {
+	if mov.Info.Type != rms_library.MovieType_TvSeries {
+		return true
+	}
+	newCount := countSeasonEpisodes(mov.TorrentEpisodes(newTorrent))
+	oldCount := countSeasonEpisodes(mov.TorrentEpisodes(oldTorrent))
+	if len(newCount) == 0 {
+		return false
+	}
+	for season, count := range oldCount {
+		if newCount[season] < count {
+			return false
+		}
+	}
+	return true
+}
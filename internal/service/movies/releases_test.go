@@ -0,0 +1,170 @@
+package movies
+
+import (
+	"testing"
+
+	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+	"github.com/RacoonMediaServer/rms-media-discovery/pkg/client/models"
+	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
+	"github.com/stretchr/testify/assert"
+)
+
+func makeReleases(titles ...string) []*models.SearchTorrentsResult {
+	result := make([]*models.SearchTorrentsResult, 0, len(titles))
+	for i := range titles {
+		result = append(result, &models.SearchTorrentsResult{Title: &titles[i]})
+	}
+	return result
+}
+
+func getTitles(list []*models.SearchTorrentsResult) []string {
+	result := []string{}
+	for _, r := range list {
+		result = append(result, *r.Title)
+	}
+	return result
+}
+
+func TestFilterMoreCompleteReleases(t *testing.T) {
+	list := makeReleases(
+		"Severance.S02E01-E06.1080p.WEB-DL",
+		"Severance.S02E01-E08.1080p.WEB-DL",
+		"Severance.S02E03-E08.1080p.WEB-DL",
+		"Severance.S02.1080p.WEB-DL",
+	)
+	list = append(list, &models.SearchTorrentsResult{})
+
+	type testCase struct {
+		lastEpisode uint
+		hasGaps     bool
+		result      []string
+	}
+
+	testCases := []testCase{
+		{lastEpisode: 6, hasGaps: false, result: []string{"Severance.S02E01-E08.1080p.WEB-DL"}},
+		{lastEpisode: 6, hasGaps: true, result: []string{"Severance.S02E01-E06.1080p.WEB-DL", "Severance.S02E01-E08.1080p.WEB-DL", "Severance.S02.1080p.WEB-DL"}},
+		{lastEpisode: 8, hasGaps: false, result: []string{}},
+		{lastEpisode: 8, hasGaps: true, result: []string{"Severance.S02E01-E08.1080p.WEB-DL", "Severance.S02.1080p.WEB-DL"}},
+		{lastEpisode: 2, hasGaps: false, result: []string{"Severance.S02E01-E06.1080p.WEB-DL", "Severance.S02E01-E08.1080p.WEB-DL"}},
+	}
+
+	for i, tc := range testCases {
+		actual := filterMoreCompleteReleases(list, tc.lastEpisode, tc.hasGaps)
+		assert.Equal(t, tc.result, getTitles(actual), "Test %d failed", i)
+	}
+}
+
+func TestGetSeasonTorrent(t *testing.T) {
+	mov := &model.Movie{Episodes: []model.Episode{
+		{Season: 1, No: 1, TorrentID: "a"},
+		{Season: 1, No: 2, TorrentID: "b"},
+		{Season: 1, No: 3, TorrentID: "b"},
+		{Season: 2, No: 1, TorrentID: "c"},
+	}}
+
+	assert.Equal(t, "b", getSeasonTorrent(mov, 1))
+	assert.Equal(t, "c", getSeasonTorrent(mov, 2))
+	assert.Equal(t, "", getSeasonTorrent(mov, 3))
+}
+
+func TestCountSeasonEpisodes(t *testing.T) {
+	type testCase struct {
+		episodes []model.Episode
+		result   map[uint]int
+	}
+
+	testCases := []testCase{
+		{episodes: nil, result: map[uint]int{}},
+		{
+			episodes: []model.Episode{{Season: 1, No: 1}, {Season: 1, No: 2}, {Season: 2, No: 1}},
+			result:   map[uint]int{1: 2, 2: 1},
+		},
+		{
+			// дубли эпизода (другое качество, другой путь) считаются один раз
+			episodes: []model.Episode{{Season: 1, No: 1, Path: "a.mkv"}, {Season: 1, No: 1, Path: "b.mkv"}},
+			result:   map[uint]int{1: 1},
+		},
+	}
+
+	for i, tc := range testCases {
+		assert.Equal(t, tc.result, countSeasonEpisodes(tc.episodes), "Test %d failed", i)
+	}
+}
+
+func TestIsBetterReplacement(t *testing.T) {
+	newSeries := func(episodes ...model.Episode) *model.Movie {
+		return &model.Movie{Info: rms_library.MovieInfo{Type: rms_library.MovieType_TvSeries}, Episodes: episodes}
+	}
+
+	type testCase struct {
+		mov    *model.Movie
+		result bool
+	}
+
+	testCases := []testCase{
+		{
+			mov:    &model.Movie{Info: rms_library.MovieInfo{Type: rms_library.MovieType_Film}},
+			result: true,
+		},
+		{
+			mov: newSeries(
+				model.Episode{Season: 1, No: 1, TorrentID: "old"},
+				model.Episode{Season: 1, No: 1, TorrentID: "new"},
+				model.Episode{Season: 1, No: 2, TorrentID: "new"},
+			),
+			result: true,
+		},
+		{
+			mov: newSeries(
+				model.Episode{Season: 1, No: 1, TorrentID: "old"},
+				model.Episode{Season: 1, No: 2, TorrentID: "old"},
+				model.Episode{Season: 1, No: 1, TorrentID: "new"},
+				model.Episode{Season: 2, No: 1, TorrentID: "new"},
+			),
+			result: false,
+		},
+		{
+			mov:    newSeries(model.Episode{Season: 1, No: 1, TorrentID: "old"}),
+			result: false,
+		},
+		{
+			// о новой раздаче ничего не известно
+			mov:    newSeries(),
+			result: false,
+		},
+	}
+
+	for i, tc := range testCases {
+		assert.Equal(t, tc.result, isBetterReplacement(tc.mov, "new", "old"), "Test %d failed", i)
+	}
+}
+
+func TestGetMissingEpisodes(t *testing.T) {
+	mov := &model.Movie{
+		Episodes: []model.Episode{{Season: 1, No: 1}, {Season: 1, No: 3}, {Season: 2, No: 1}},
+		ListItem: model.ListItem{Torrents: []model.TorrentRecord{
+			{ID: "a", Predicted: []model.Episode{{Season: 1, No: 4}}},
+		}},
+	}
+
+	assert.Equal(t, []uint{2}, getMissingEpisodes(mov, 1))
+	assert.Empty(t, getMissingEpisodes(mov, 2))
+
+	mov.SeasonLengths = []uint{6, 1}
+	assert.Equal(t, []uint{2, 5, 6}, getMissingEpisodes(mov, 1))
+	assert.Empty(t, getMissingEpisodes(mov, 2))
+	assert.Empty(t, getMissingEpisodes(mov, 3))
+}
+
+func TestFilterCompleteReleases(t *testing.T) {
+	list := makeReleases(
+		"Severance.S02E01-E06.1080p.WEB-DL",
+		"Severance.S02E01-E08.1080p.WEB-DL",
+		"Severance.S02E03-E10.1080p.WEB-DL",
+		"Severance.S02.1080p.WEB-DL",
+	)
+	list = append(list, &models.SearchTorrentsResult{})
+
+	assert.Equal(t, []string{"Severance.S02E01-E08.1080p.WEB-DL", "Severance.S02.1080p.WEB-DL"}, getTitles(filterCompleteReleases(list, 7)))
+	assert.Equal(t, []string{"Severance.S02.1080p.WEB-DL"}, getTitles(filterCompleteReleases(list, 10)))
+}
@@ -2,11 +2,14 @@ package movies
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/RacoonMediaServer/rms-library/v3/internal/config"
 	"github.com/RacoonMediaServer/rms-library/v3/internal/lock"
 	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/metainfo"
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/selector"
 	"github.com/RacoonMediaServer/rms-media-discovery/pkg/client/client"
 	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
 	"github.com/RacoonMediaServer/rms-packages/pkg/service/servicemgr"
@@ -23,21 +26,53 @@ const searchTorrentsLimit uint = 10
 
 // MoviesService is a service API handler
 type MoviesService struct {
-	f     servicemgr.ServiceFactory
-	auth  runtime.ClientAuthInfoWriter
-	db    Database
-	cli   *client.Client
-	dir   DirectoryManager
-	dm    DownloadsManager
-	sched Scheduler
-	lk    lock.Locker
-	pub   micro.Event
+	f       servicemgr.ServiceFactory
+	auth    runtime.ClientAuthInfoWriter
+	db      Database
+	cli     *client.Client
+	dir     DirectoryManager
+	dm      DownloadsManager
+	sched   Scheduler
+	lk      lock.Locker
+	pub     micro.Event
+	archive Archive
+
+	upgrade      config.Upgrade
+	rules        selector.Rules
+	ranking      ranking
+	audio        config.Audio
+	verification config.Verification
+	retention    config.Retention
+}
+
+type ranking struct {
+	pipelines  map[selector.Criteria]selector.Pipeline
+	codecs     []string
+	trackers   []selector.TrackerReputation
+	lists      map[rms_library.List]selector.Criteria
+	minQuality string
 }
 
 // AddClip implements rms_library.MoviesHandler.
 func (l *MoviesService) AddClip(ctx context.Context, req *rms_library.MoviesAddClipRequest, resp *rms_library.MoviesAddClipResponse) error {
+	mi, err := metainfo.Load(req.Torrent)
+	if err != nil {
+		return err
+	}
+	owner, err := l.db.FindTorrentOwner(ctx, mi.InfoHash)
+	if err != nil {
+		logger.Errorf("Check torrent duplicates failed: %s", err)
+		return err
+	}
+	if owner != nil {
+		return fmt.Errorf("torrent is already added to '%s' [ %s ]", owner.Title, owner.ID)
+	}
+
 	id := model.MakeID("clip_"+uuid.New().String(), rms_library.ContentType_TypeMovies)
 	title := id.Strip()
+	if mi.Magnet && mi.Name != "" {
+		title = mi.Name
+	}
 	if req.Title != nil {
 		title = *req.Title
 	}
@@ -106,6 +141,13 @@ type Settings struct {
 	Scheduler        Scheduler
 	Locker           lock.Locker
 	Publisher        micro.Event
+	Upgrade          config.Upgrade
+	Rules            selector.Rules
+	Ranking          config.Ranking
+	Audio            config.Audio
+	Verification     config.Verification
+	Retention        config.Retention
+	Archive          Archive
 }
 
 func NewService(settings Settings) *MoviesService {
@@ -114,16 +156,40 @@ func NewService(settings Settings) *MoviesService {
 	auth := httptransport.APIKeyAuth("X-Token", "header", settings.Device)
 	discoveryClient := client.New(tr, strfmt.Default)
 
+	// настройки ранжирования проверяются при старте
+	pipelines, err := settings.Ranking.GetPipelines()
+	if err != nil {
+		logger.Warnf("Ranking pipelines are ignored: %s", err)
+	}
+	lists, err := settings.Ranking.GetListCriteria()
+	if err != nil {
+		logger.Warnf("Criteria of lists are ignored: %s", err)
+	}
+
 	l := &MoviesService{
-		f:     settings.ServiceFactory,
-		auth:  auth,
-		db:    settings.Database,
-		cli:   discoveryClient,
-		dir:   settings.DirectoryManager,
-		dm:    settings.DownloadsManager,
-		sched: settings.Scheduler,
-		lk:    settings.Locker,
-		pub:   settings.Publisher,
+		f:       settings.ServiceFactory,
+		auth:    auth,
+		db:      settings.Database,
+		cli:     discoveryClient,
+		dir:     settings.DirectoryManager,
+		dm:      settings.DownloadsManager,
+		sched:   settings.Scheduler,
+		lk:      settings.Locker,
+		pub:     settings.Publisher,
+		archive: settings.Archive,
+
+		upgrade: settings.Upgrade,
+		rules:   settings.Rules,
+		ranking: ranking{
+			pipelines:  pipelines,
+			codecs:     settings.Ranking.Codecs,
+			trackers:   settings.Ranking.Trackers,
+			lists:      lists,
+			minQuality: settings.Ranking.MinQuality,
+		},
+		audio:        settings.Audio,
+		verification: settings.Verification,
+		retention:    settings.Retention,
 	}
 
 	return l
@@ -3,8 +3,12 @@ package movies
 import (
 	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
 	"github.com/RacoonMediaServer/rms-library/v3/pkg/selector"
+	"github.com/RacoonMediaServer/rms-media-discovery/pkg/media"
+	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
 )
 
+var qualityPrior = []string{"1080p", "720p", "480p"}
+
 func (l MoviesService) getMovieSelector(mov *model.Movie) selector.MediaSelector {
 	// TODO: вынести в настройки
 
@@ -12,16 +16,44 @@ func (l MoviesService) getMovieSelector(mov *model.Movie) selector.MediaSelector
 		MinSeasonSizeMB:     1024,
 		MaxSeasonSizeMB:     50 * 1024,
 		MinSeedersThreshold: 50,
-		QualityPrior:        []string{"1080p", "720p", "480p"},
+		QualityPrior:        qualityPrior,
 		Voice:               mov.Voice,
+		Rules:               l.rules.Merge(mov.GetRules()),
+		Pipelines:           l.ranking.pipelines,
+		CodecPrior:          l.ranking.codecs,
+		Trackers:            l.ranking.trackers,
+		MinQuality:          l.ranking.minQuality,
+		Languages:           l.audio.Languages,
+		RequireOriginal:     l.audio.RequireOriginal,
+		SubtitleLanguages:   l.audio.Subtitles,
 	}
 
-	settings.VoiceList.Append("сыендук", "syenduk")
-	settings.VoiceList.Append("кубик", "кубе", "kubik", "kube")
-	settings.VoiceList.Append("кураж", "бомбей", "kurazh", "bombej")
-	settings.VoiceList.Append("lostfilm", "lost")
-	settings.VoiceList.Append("newstudio")
-	settings.VoiceList.Append("амедиа", "amedia")
+	if len(l.audio.Voices) != 0 {
+		for _, names := range l.audio.Voices {
+			settings.VoiceList.Append(names...)
+		}
+	} else {
+		settings.VoiceList.Append("сыендук", "syenduk")
+		settings.VoiceList.Append("кубик", "кубе", "kubik", "kube")
+		settings.VoiceList.Append("кураж", "бомбей", "kurazh", "bombej")
+		settings.VoiceList.Append("lostfilm", "lost")
+		settings.VoiceList.Append("newstudio")
+		settings.VoiceList.Append("амедиа", "amedia")
+	}
 
 	return selector.New(settings)
 }
+
+func (l MoviesService) getSelectorOptions(mov *model.Movie) selector.Options {
+	opts := selector.Options{
+		Criteria:  selector.CriteriaQuality,
+		MediaType: media.Movies,
+		Query:     mov.Info.Title,
+	}
+	if criteria, ok := l.ranking.lists[mov.List]; ok {
+		opts.Criteria = criteria
+	} else if mov.List == rms_library.List_WatchList {
+		opts.Criteria = selector.CriteriaFastest
+	}
+	return opts
+}
@@ -4,20 +4,27 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/api"
 	"github.com/RacoonMediaServer/rms-library/v3/pkg/movsearch"
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/selector"
 	"github.com/RacoonMediaServer/rms-media-discovery/pkg/client/models"
 	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
 )
 
-func convertTorrent(result *models.SearchTorrentsResult) *rms_library.Torrent {
+// convertTorrent converts the search result, returns false if some of required fields are absent
+func convertTorrent(result *models.SearchTorrentsResult) (*rms_library.Torrent, bool) {
+	if result == nil || result.Link == nil || result.Title == nil || result.Size == nil || result.Seeders == nil {
+		return nil, false
+	}
 	return &rms_library.Torrent{
 		Id:      *result.Link,
 		Title:   *result.Title,
 		Size:    uint64(*result.Size),
 		Seeders: uint32(*result.Seeders),
-	}
+	}, true
 }
 
 func (l MoviesService) GetTorrentContent(ctx context.Context, torrentId string) ([]byte, error) {
@@ -25,24 +32,84 @@ func (l MoviesService) GetTorrentContent(ctx context.Context, torrentId string)
 	return searchEngine.GetTorrentFile(ctx, torrentId)
 }
 
-func (l MoviesService) FindTorrents(ctx context.Context, id model.ID, season *uint) ([]*rms_library.Torrent, error) {
+func (l MoviesService) findRankedTorrents(ctx context.Context, id model.ID, season *uint) ([]*models.SearchTorrentsResult, []selector.Explanation, error) {
 	mov, err := l.db.GetMovie(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("load movie failed: %w", err)
+		return nil, nil, fmt.Errorf("load movie failed: %w", err)
 	}
 	if mov == nil {
-		return nil, errors.New("movie not found")
+		return nil, nil, errors.New("movie not found")
 	}
 
 	searchEngine := movsearch.NewRemoteSearchEngine(l.cli.Torrents, l.auth)
 	resp, err := searchEngine.SearchTorrents(ctx, mov.ID.String(), &mov.Info, season)
 	if err != nil {
-		return nil, fmt.Errorf("search torrents failed: %s", err)
+		return nil, nil, fmt.Errorf("search torrents failed: %s", err)
+	}
+
+	sel := l.getMovieSelector(mov)
+	opts := l.getSelectorOptions(mov)
+	resp = sel.Filter(resp, opts)
+	explanations, err := sel.Explain(resp, opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("rank torrents failed: %w", err)
+	}
+	order := make([]int, len(resp))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return explanations[order[j]].Total < explanations[order[i]].Total })
+
+	sortedResp := make([]*models.SearchTorrentsResult, len(resp))
+	sortedExplanations := make([]selector.Explanation, len(resp))
+	for i, idx := range order {
+		sortedResp[i] = resp[idx]
+		sortedExplanations[i] = explanations[idx]
+	}
+
+	return sortedResp, sortedExplanations, nil
+}
+
+func (l MoviesService) FindTorrents(ctx context.Context, id model.ID, season *uint) ([]*rms_library.Torrent, error) {
+	resp, _, err := l.findRankedTorrents(ctx, id, season)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*rms_library.Torrent, 0, len(resp))
+	for i := range resp {
+		if t, ok := convertTorrent(resp[i]); ok {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+func (l MoviesService) ExplainTorrents(ctx context.Context, id model.ID, season *uint) ([]*api.RankedTorrent, error) {
+	resp, explanations, err := l.findRankedTorrents(ctx, id, season)
+	if err != nil {
+		return nil, err
 	}
 
-	result := make([]*rms_library.Torrent, len(resp))
+	result := make([]*api.RankedTorrent, 0, len(resp))
 	for i := range resp {
-		result[i] = convertTorrent(resp[i])
+		t, ok := convertTorrent(resp[i])
+		if !ok {
+			continue
+		}
+		ranked := &api.RankedTorrent{
+			Id:      t.Id,
+			Title:   t.Title,
+			Size:    t.Size,
+			Seeders: t.Seeders,
+			Quality: resp[i].Quality,
+			Voice:   resp[i].Voice,
+			Rank:    explanations[i].Total,
+		}
+		for _, score := range explanations[i].Scores {
+			ranked.Scores = append(ranked.Scores, &api.Score{Name: score.Name, Weight: score.Weight, Rank: score.Rank})
+		}
+		result = append(result, ranked)
 	}
 	return result, nil
 }
@@ -0,0 +1,34 @@
+package movies
+
+import (
+	"testing"
+
+	"github.com/RacoonMediaServer/rms-media-discovery/pkg/client/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertTorrent(t *testing.T) {
+	link := "link"
+	title := "Severance.S02.1080p.WEB-DL"
+	size := int64(1024)
+	seeders := int64(10)
+
+	testCases := []*models.SearchTorrentsResult{
+		nil,
+		{Title: &title, Size: &size, Seeders: &seeders},
+		{Link: &link, Size: &size, Seeders: &seeders},
+		{Link: &link, Title: &title, Seeders: &seeders},
+		{Link: &link, Title: &title, Size: &size},
+	}
+	for i, tc := range testCases {
+		_, ok := convertTorrent(tc)
+		assert.False(t, ok, "Test %d failed", i)
+	}
+
+	torrent, ok := convertTorrent(&models.SearchTorrentsResult{Link: &link, Title: &title, Size: &size, Seeders: &seeders})
+	assert.True(t, ok)
+	assert.Equal(t, link, torrent.Id)
+	assert.Equal(t, title, torrent.Title)
+	assert.Equal(t, uint64(1024), torrent.Size)
+	assert.Equal(t, uint32(10), torrent.Seeders)
+}
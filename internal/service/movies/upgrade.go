@@ -0,0 +1,204 @@
+package movies
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/RacoonMediaServer/rms-library/v3/internal/analysis"
+	"github.com/RacoonMediaServer/rms-library/v3/internal/lock"
+	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/movsearch"
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/selector"
+	"github.com/RacoonMediaServer/rms-media-discovery/pkg/client/models"
+	"github.com/RacoonMediaServer/rms-media-discovery/pkg/media"
+	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
+	"go-micro.dev/v4/logger"
+)
+
+const defaultUpgradeInterval = 72 * time.Hour
+
+func (l MoviesService) getUpgradeInterval() time.Duration {
+	if l.upgrade.IntervalHours == 0 {
+		return defaultUpgradeInterval
+	}
+	return time.Duration(l.upgrade.IntervalHours) * time.Hour
+}
+
+func guessQuality(title string, qualities []string) string {
+	// сначала пробуем тег качества, найденный анализатором
+	if q := analysis.Analyze(title).Tags.Quality; q != "" && slices.Contains(qualities, q) {
+		return q
+	}
+
+	title = strings.ToLower(title)
+	for _, q := range qualities {
+		if strings.Contains(title, q) {
+			return q
+		}
+	}
+	return ""
+}
+
+// makeCurrentRelease represents the downloaded torrent as a search result for ranking.
+// If the release is still distributed, the actual search result is used
+func makeCurrentRelease(t *model.TorrentRecord, found []*models.SearchTorrentsResult, qualities []string) *models.SearchTorrentsResult {
+	if t.Release != nil && t.Release.Link != "" {
+		for _, r := range found {
+			if r.Link != nil && *r.Link == t.Release.Link {
+				return r
+			}
+		}
+	}
+
+	size := int64(t.Size)
+	seeders := medianSeeders(found)
+	result := &models.SearchTorrentsResult{
+		Link:    &t.ID,
+		Title:   &t.Title,
+		Size:    &size,
+		Seeders: &seeders,
+	}
+
+	if t.Release == nil {
+		result.Quality = guessQuality(t.Title, qualities)
+		return result
+	}
+
+	result.Quality = t.Release.Quality
+	result.Voice = t.Release.Voice
+	if t.Release.Title != "" {
+		result.Title = &t.Release.Title
+	}
+	if t.Release.SizeMB != 0 {
+		size = int64(t.Release.SizeMB)
+	}
+	if t.Release.Seeders != 0 {
+		seeders = t.Release.Seeders
+	}
+	return result
+}
+
+// medianSeeders estimates popularity of the release with unknown seeders, so it doesn't affect the rank gain
+func medianSeeders(list []*models.SearchTorrentsResult) int64 {
+	seeders := make([]int64, 0, len(list))
+	for _, r := range list {
+		if r.Seeders != nil {
+			seeders = append(seeders, *r.Seeders)
+		}
+	}
+	if len(seeders) == 0 {
+		return 0
+	}
+	slices.Sort(seeders)
+	return seeders[len(seeders)/2]
+}
+
+// getUpgradableTorrents returns all downloaded torrents of the item
+func getUpgradableTorrents(mov *model.Movie) []*model.TorrentRecord {
+	var result []*model.TorrentRecord
+	for i := range mov.Torrents {
+		if !mov.Torrents[i].Online {
+			result = append(result, &mov.Torrents[i])
+		}
+	}
+	return result
+}
+
+// isDownloadedRelease checks the search result is one of the releases of the item torrents
+func isDownloadedRelease(r *models.SearchTorrentsResult, torrents []*model.TorrentRecord) bool {
+	return slices.ContainsFunc(torrents, func(t *model.TorrentRecord) bool {
+		return t.Release != nil && t.Release.Link != "" && r.Link != nil && *r.Link == t.Release.Link
+	})
+}
+
+func (l MoviesService) asyncCheckUpgrade(log logger.Logger, ctx context.Context, id model.ID) error {
+	lk, err := lock.TimedLock(ctx, l.lk, id, lockWait)
+	if err != nil {
+		return fmt.Errorf("Lock item failed: %w", err)
+	}
+	defer lk.Unlock()
+
+	mov, err := l.db.GetMovie(ctx, id)
+	if err != nil {
+		return fmt.Errorf("load movie from database failed: %w", err)
+	}
+	if mov == nil {
+		return errors.New("movie not found")
+	}
+
+	if mov.List != rms_library.List_Favourites || hasPendingReplacements(mov) {
+		return nil
+	}
+	torrents := getUpgradableTorrents(mov)
+	if len(torrents) == 0 {
+		return nil
+	}
+
+	searchEngine := movsearch.NewRemoteSearchEngine(l.cli.Torrents, l.auth)
+	found, err := searchEngine.SearchTorrents(ctx, mov.ID.String(), &mov.Info, nil)
+	if err != nil {
+		return fmt.Errorf("search torrents failed: %w", err)
+	}
+	sel := l.getMovieSelector(mov)
+	opts := selector.Options{
+		Criteria:  selector.CriteriaQuality,
+		MediaType: media.Movies,
+		Query:     mov.Info.Title,
+	}
+
+	found = sel.Filter(found, opts)
+	result := slices.DeleteFunc(slices.Clone(found), func(r *models.SearchTorrentsResult) bool {
+		return isDownloadedRelease(r, torrents)
+	})
+	if len(result) == 0 {
+		return nil
+	}
+
+	// ранжируем все скачанные раздачи вместе с кандидатами, за основу берется лучшая из скачанных
+	list := make([]*models.SearchTorrentsResult, 0, len(torrents)+len(result))
+	for _, t := range torrents {
+		list = append(list, makeCurrentRelease(t, found, qualityPrior))
+	}
+	list = append(list, result...)
+	ranks, err := sel.Rank(list, opts)
+	if err != nil {
+		return fmt.Errorf("rank torrents failed: %w", err)
+	}
+
+	current := 0
+	for i := 1; i < len(torrents); i++ {
+		if ranks[i] > ranks[current] {
+			current = i
+		}
+	}
+	best := len(torrents)
+	for i := best + 1; i < len(ranks); i++ {
+		if ranks[i] > ranks[best] {
+			best = i
+		}
+	}
+
+	gain := ranks[best] - ranks[current]
+	if gain < l.upgrade.Threshold {
+		log.Logf(logger.DebugLevel, "Better release not found (gain %.4f)", gain)
+		return nil
+	}
+
+	selected := list[best]
+	log.Logf(logger.InfoLevel, "Found better release '%s' [ %s, %s ], gain %.4f", *selected.Title, selected.Quality, selected.Voice, gain)
+
+	torrentFile, err := searchEngine.GetTorrentFile(ctx, *selected.Link)
+	if err != nil {
+		return fmt.Errorf("get torrent file failed: %w", err)
+	}
+
+	if err = l.dm.DownloadRelease(ctx, &mov.ListItem, torrentFile, model.NewRelease(selected), torrents[current].ID); err != nil {
+		return fmt.Errorf("download better release failed: %w", err)
+	}
+
+	return nil
+}
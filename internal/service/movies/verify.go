@@ -0,0 +1,95 @@
+package movies
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/RacoonMediaServer/rms-library/v3/internal/analysis"
+	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+	"github.com/RacoonMediaServer/rms-packages/pkg/events"
+	"go-micro.dev/v4/logger"
+)
+
+// watcherVerifyTitles checks downloaded torrents contain the movie, not the namesake or another part
+func (l MoviesService) watcherVerifyTitles(log logger.Logger, ctx context.Context, mov *model.Movie) {
+	changed := false
+	var removable, suspicious []model.TorrentRecord
+	for i := range mov.Torrents {
+		t := &mov.Torrents[i]
+		if t.Verified || t.Location == "" {
+			continue
+		}
+		done, err := l.dm.IsDownloaded(ctx, t)
+		if err != nil {
+			log.Logf(logger.WarnLevel, "Get status of torrent '%s' failed: %s", t.Title, err)
+			continue
+		}
+		if !done {
+			continue
+		}
+
+		files, err := l.dir.MoviesAnalyzeFiles(t)
+		if err != nil {
+			log.Logf(logger.WarnLevel, "Analyze files of '%s' failed: %s", t.Title, err)
+			continue
+		}
+
+		m := analysis.VerifyTitle(&mov.Info, files)
+		t.Verified = true
+		t.Mismatch = m.String()
+		changed = true
+		if m.IsEmpty() {
+			continue
+		}
+		log.Logf(logger.WarnLevel, "Torrent '%s' [ %s ] doesn't match the movie: %s", t.Title, t.ID, t.Mismatch)
+		if m.IsCertain() {
+			removable = append(removable, *t)
+		} else {
+			suspicious = append(suspicious, *t)
+		}
+	}
+
+	if changed {
+		if err := l.db.UpdateContent(ctx, mov.ID, mov.Torrents); err != nil {
+			log.Logf(logger.WarnLevel, "Update torrents failed: %s", err)
+			return
+		}
+	}
+
+	// удаляем только если не совпали и название, и год, иначе лишь предупреждаем пользователя
+	if !l.verification.RemoveMismatched {
+		suspicious = append(suspicious, removable...)
+		removable = nil
+	}
+	for _, t := range suspicious {
+		// скачивание прошло успешно, поэтому предупреждение отправляем нейтральным уведомлением с описанием несовпадения
+		l.notifyTorrent(log, ctx, mov, &t, events.Notification_ContentFound, fmt.Sprintf("%s (possible mismatch: %s)", mov.Title, t.Mismatch))
+	}
+
+	for _, t := range removable {
+		l.blockRelease(log, ctx, mov, &t)
+		if err := l.dm.RemoveTorrent(ctx, &mov.ListItem, t.ID); err != nil {
+			log.Logf(logger.WarnLevel, "Remove mismatched torrent '%s' failed: %s", t.Title, err)
+			continue
+		}
+		log.Logf(logger.InfoLevel, "Mismatched torrent '%s' [ %s ] removed", t.Title, t.ID)
+		l.notifyTorrent(log, ctx, mov, &t, events.Notification_TorrentRemoved, mov.Title)
+	}
+}
+
+func (l MoviesService) notifyTorrent(log logger.Logger, ctx context.Context, mov *model.Movie, t *model.TorrentRecord, kind events.Notification_Kind, title string) {
+	nCtx, nCancel := context.WithTimeout(ctx, notifyTimeout)
+	defer nCancel()
+
+	event := events.Notification{
+		Sender:    "rms-library",
+		Kind:      kind,
+		TorrentID: &t.ID,
+		MediaID:   (*string)(&mov.ID),
+		ItemTitle: &title,
+	}
+
+	if err := l.pub.Publish(nCtx, &event); err != nil {
+		log.Logf(logger.WarnLevel, "Send notification about torrent failed: %s", err)
+	}
+}
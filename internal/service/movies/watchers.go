@@ -7,14 +7,11 @@ import (
 	"math/rand"
 	"time"
 
+	"github.com/RacoonMediaServer/rms-library/v3/internal/analysis"
 	"github.com/RacoonMediaServer/rms-library/v3/internal/lock"
 	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
 	"github.com/RacoonMediaServer/rms-library/v3/internal/schedule"
-	"github.com/RacoonMediaServer/rms-library/v3/pkg/movsearch"
-	"github.com/RacoonMediaServer/rms-library/v3/pkg/selector"
 	"github.com/RacoonMediaServer/rms-media-discovery/pkg/client/client/movies"
-	"github.com/RacoonMediaServer/rms-media-discovery/pkg/media"
-	"github.com/RacoonMediaServer/rms-packages/pkg/events"
 	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
 	"go-micro.dev/v4/logger"
 	"golang.org/x/exp/slices"
@@ -22,6 +19,9 @@ import (
 
 const lockWait = 5 * time.Second
 
+// replacementTimeout is a time, after which not downloaded replacement is dropped
+const replacementTimeout = 7 * 24 * time.Hour
+
 func isContentMissing(mov *model.Movie) bool {
 	switch mov.List {
 	case rms_library.List_Archive:
@@ -50,6 +50,12 @@ func isContentMissing(mov *model.Movie) bool {
 	return false
 }
 
+// Watch restarts all periodic tasks of the item, it is used when the item is restored from backup
+func (l MoviesService) Watch(mov *model.Movie) {
+	l.sched.Cancel(mov.ID.String())
+	l.startWatchers(mov)
+}
+
 func (l MoviesService) startWatchers(mov *model.Movie) {
 	// periodic task for validate movie record
 	task := schedule.Task{
@@ -88,6 +94,46 @@ func (l MoviesService) startWatchers(mov *model.Movie) {
 		schedTask.After(time.Duration(rand.Intn(24)) * time.Hour)
 		l.sched.Add(&schedTask)
 	}
+
+	if mov.Info.Type == rms_library.MovieType_Film && l.upgrade.Enabled {
+		// periodic task for search better releases
+		upgradeTask := schedule.Task{
+			Group: mov.ID.String(),
+			Fn: schedule.GetPeriodicWrapper(
+				logger.Fields(map[string]interface{}{
+					"op":    "movieUpgradeWatcher",
+					"id":    mov.ID.String(),
+					"title": mov.Info.Title,
+				}),
+				l.getUpgradeInterval(),
+				func(log logger.Logger, ctx context.Context) error {
+					return l.asyncCheckUpgrade(log, ctx, mov.ID)
+				},
+			),
+		}
+		upgradeTask.After(time.Duration(rand.Intn(24)) * time.Hour)
+		l.sched.Add(&upgradeTask)
+	}
+
+	if mov.List == rms_library.List_Archive && mov.Info.Type != rms_library.MovieType_Clip {
+		// periodic task for refresh archived candidates
+		refreshTask := schedule.Task{
+			Group: mov.ID.String(),
+			Fn: schedule.GetPeriodicWrapper(
+				logger.Fields(map[string]interface{}{
+					"op":    "movieArchiveRefreshWatcher",
+					"id":    mov.ID.String(),
+					"title": mov.Info.Title,
+				}),
+				l.getArchiveRefreshInterval(),
+				func(log logger.Logger, ctx context.Context) error {
+					return l.asyncRefreshArchive(log, ctx, mov.ID)
+				},
+			),
+		}
+		refreshTask.After(l.getArchiveRefreshInterval() + time.Duration(rand.Intn(24))*time.Hour)
+		l.sched.Add(&refreshTask)
+	}
 }
 
 func (l MoviesService) asyncWatch(log logger.Logger, ctx context.Context, id model.ID) error {
@@ -125,6 +171,21 @@ func (l MoviesService) asyncWatch(log logger.Logger, ctx context.Context, id mod
 	// 4) синхронизируем информацию о торрентах
 	l.watcherSyncTorrentInfo(log, ctx, mov)
 
+	// 5) обновляем список скачанных эпизодов сериала
+	if mov.Info.Type == rms_library.MovieType_TvSeries && mov.List != rms_library.List_Archive {
+		l.watcherUpdateEpisodes(log, ctx, mov)
+	}
+
+	// 6) проверяем, что скачали именно то, что искали
+	if mov.List != rms_library.List_Archive && mov.Info.Type != rms_library.MovieType_Clip {
+		l.watcherVerifyTitles(log, ctx, mov)
+	}
+
+	// 7) заменяем старые раздачи на скачанные более полные или качественные
+	if mov.List != rms_library.List_Archive {
+		l.watcherResolveReplacements(log, ctx, mov)
+	}
+
 	return nil
 }
 
@@ -174,6 +235,116 @@ func (l MoviesService) watcherSyncTorrentInfo(log logger.Logger, ctx context.Con
 	}
 }
 
+func (l MoviesService) watcherUpdateEpisodes(log logger.Logger, ctx context.Context, mov *model.Movie) {
+	episodes := []model.Episode{}
+	for i := range mov.Torrents {
+		t := &mov.Torrents[i]
+		if t.Location == "" {
+			continue
+		}
+		found, err := l.dir.MoviesScanEpisodes(t, analysis.NewNumbering(mov))
+		if err != nil {
+			log.Logf(logger.DebugLevel, "Scan episodes of '%s' failed: %s", t.Title, err)
+			continue
+		}
+		episodes = append(episodes, found...)
+	}
+
+	if slices.Equal(episodes, mov.Episodes) {
+		return
+	}
+
+	mov.Episodes = episodes
+	if err := l.db.UpdateMovieEpisodes(ctx, mov); err != nil {
+		log.Logf(logger.WarnLevel, "Update episodes inventory failed: %s", err)
+	}
+}
+
+func (l MoviesService) watcherResolveReplacements(log logger.Logger, ctx context.Context, mov *model.Movie) {
+	for _, t := range slices.Clone(mov.Torrents) {
+		if t.Replaces == "" {
+			continue
+		}
+		done, err := l.dm.IsDownloaded(ctx, &t)
+		if err != nil {
+			log.Logf(logger.WarnLevel, "Get status of torrent '%s' failed: %s", t.Title, err)
+			continue
+		}
+		if !done {
+			l.dropStalledReplacement(log, ctx, mov, &t)
+			continue
+		}
+
+		replacement := mov.GetTorrent(t.ID)
+		if replacement == nil {
+			continue
+		}
+		oldTorrent := t.Replaces
+		replacement.Replaces = ""
+
+		toRemove := t.ID
+		if mov.GetTorrent(oldTorrent) == nil || isBetterReplacement(mov, t.ID, oldTorrent) {
+			toRemove = oldTorrent
+		}
+
+		if mov.GetTorrent(toRemove) == nil {
+			if err = l.db.UpdateContent(ctx, mov.ID, mov.Torrents); err != nil {
+				log.Logf(logger.WarnLevel, "Update torrents failed: %s", err)
+			}
+			continue
+		}
+
+		if toRemove == oldTorrent {
+			log.Logf(logger.InfoLevel, "Torrent '%s' replaced by '%s'", oldTorrent, t.ID)
+		} else {
+			log.Logf(logger.InfoLevel, "Torrent '%s' is not better than '%s', drop it", t.ID, oldTorrent)
+			// запоминаем отвергнутую раздачу, иначе на следующем цикле она будет скачана снова
+			l.blockRelease(log, ctx, mov, &t)
+		}
+		if err = l.dm.RemoveTorrent(ctx, &mov.ListItem, toRemove); err != nil {
+			log.Logf(logger.WarnLevel, "Remove replaced torrent failed: %s", err)
+		}
+	}
+}
+
+// dropStalledReplacement removes the replacement, which is downloading too long (no seeders, dead torrent),
+// otherwise it blocks searching of more complete releases forever
+func (l MoviesService) dropStalledReplacement(log logger.Logger, ctx context.Context, mov *model.Movie, t *model.TorrentRecord) {
+	if t.AddedAt.IsZero() {
+		// раздачи, добавленные до появления времени добавления, отсчитываем с текущего момента
+		if record := mov.GetTorrent(t.ID); record != nil {
+			record.AddedAt = time.Now()
+			if err := l.db.UpdateContent(ctx, mov.ID, mov.Torrents); err != nil {
+				log.Logf(logger.WarnLevel, "Update torrents failed: %s", err)
+			}
+		}
+		return
+	}
+	if time.Since(t.AddedAt) < replacementTimeout {
+		return
+	}
+
+	log.Logf(logger.InfoLevel, "Replacement '%s' [ %s ] is not downloaded since %s, drop it", t.Title, t.ID, t.AddedAt.Format(time.DateOnly))
+	l.blockRelease(log, ctx, mov, t)
+	if err := l.dm.RemoveTorrent(ctx, &mov.ListItem, t.ID); err != nil {
+		log.Logf(logger.WarnLevel, "Remove stalled replacement failed: %s", err)
+	}
+}
+
+// blockRelease prevents downloading the release of the torrent again
+func (l MoviesService) blockRelease(log logger.Logger, ctx context.Context, mov *model.Movie, t *model.TorrentRecord) {
+	if t.Release == nil || t.Release.Link == "" {
+		return
+	}
+	if err := l.db.BlockTorrent(ctx, mov.ID, t.Release.Link); err != nil {
+		log.Logf(logger.WarnLevel, "Block torrent '%s' failed: %s", t.Title, err)
+		return
+	}
+	if !slices.Contains(mov.Blocked, t.Release.Link) {
+		mov.Blocked = append(mov.Blocked, t.Release.Link)
+	}
+}
+
 func (l MoviesService) asyncCheckReleases(log logger.Logger, ctx context.Context, id model.ID) error {
 	lk, err := lock.TimedLock(ctx, l.lk, id, lockWait)
 	if err != nil {
@@ -196,71 +367,12 @@ func (l MoviesService) asyncCheckReleases(log logger.Logger, ctx context.Context
 	if info.Payload.Seasons == 0 || mov.Info.Seasons == nil {
 		return nil
 	}
-	if *mov.Info.Seasons >= uint32(info.Payload.Seasons) {
-		return nil
-	}
-
-	newSeasonsCount := uint32(info.Payload.Seasons) - *mov.Info.Seasons
-	log.Logf(logger.InfoLevel, "Found info about %d new seasons", newSeasonsCount)
-
-	sel := l.getMovieSelector(mov)
-	opts := selector.Options{
-		Criteria:  selector.CriteriaQuality,
-		MediaType: media.Movies,
-		Query:     mov.Info.Title,
-	}
-	if mov.List == rms_library.List_WatchList {
-		opts.Criteria = selector.CriteriaFastest
-	}
-
-	searchEngine := movsearch.NewRemoteSearchEngine(l.cli.Torrents, l.auth)
-
-	foundRealeses := []uint32{}
-	for no := uint(*mov.Info.Seasons); no < uint(info.Payload.Seasons); no++ {
-		result, err := searchEngine.SearchTorrents(ctx, mov.ID.String(), &mov.Info, &no)
-		if err != nil {
-			log.Logf(logger.WarnLevel, "Find torrents for %d season failed: %s", no, err)
-			break
-		}
-		if len(result) == 0 {
-			break
-		}
-
-		log.Logf(logger.InfoLevel, "Found new releases of %d season!", no)
-
-		if mov.List != rms_library.List_Archive {
-			selected := sel.Select(result, opts)
-			torrentFile, err := searchEngine.GetTorrentFile(ctx, *selected.Link)
-			if err != nil {
-				log.Logf(logger.WarnLevel, "Get torrent file of %d season failed: %s", no, err)
-				break
-			}
-			if err := l.dm.Download(ctx, &mov.ListItem, torrentFile); err != nil {
-				log.Logf(logger.WarnLevel, "Download new season failed: %s", err)
-				break
-			}
-		} else {
-			sel.Sort(result, opts)
-			result = boundResults(result)
-			mov.ArchivedSeasons[uint(no)] = l.fetchTorrentFiles(ctx, searchEngine, mov.Info.Title, result)
-			if err := l.db.UpdateMovieArchiveContent(ctx, mov); err != nil {
-				log.Logf(logger.WarnLevel, "update archive failed: %s", err)
-				break
-			}
-		}
-		foundRealeses = append(foundRealeses, uint32(no))
-	}
-
-	if len(foundRealeses) == 0 {
-		return nil
+	if *mov.Info.Seasons < uint32(info.Payload.Seasons) {
+		return l.checkNewSeasons(log, ctx, mov, uint(info.Payload.Seasons))
 	}
 
-	*mov.Info.Seasons += uint32(len(foundRealeses))
-	if err = l.db.UpdateMovieInfoSeasons(ctx, mov); err != nil {
-		return fmt.Errorf("update seasons count in database failed: %s", err)
+	if mov.List != rms_library.List_Archive {
+		l.checkOngoingSeason(log, ctx, mov)
 	}
-
-	log.Logf(logger.InfoLevel, "New releases %+v added!", foundRealeses)
-	l.notifyUser(log, ctx, mov, events.Notification_NewContentReleased, foundRealeses)
 	return nil
 }
@@ -0,0 +1,13 @@
+package rules
+
+import (
+	"context"
+
+	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/selector"
+)
+
+type Database interface {
+	GetListItem(ctx context.Context, id model.ID) (*model.ListItem, error)
+	UpdateListItemRules(ctx context.Context, id model.ID, rules selector.Rules) error
+}
@@ -0,0 +1,55 @@
+package rules
+
+import (
+	"context"
+	"errors"
+
+	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/api"
+	"go-micro.dev/v4/logger"
+)
+
+type Service struct {
+	Database Database
+}
+
+// Get implements api.RulesHandler.
+func (s *Service) Get(ctx context.Context, req *api.RulesGetRequest, resp *api.RulesGetResponse) error {
+	item, err := s.Database.GetListItem(ctx, model.ID(req.Id))
+	if err != nil {
+		logger.Errorf("Get item %s failed: %s", req.Id, err)
+		return err
+	}
+	if item == nil {
+		return errors.New("item not found")
+	}
+
+	resp.Rules = item.Rules
+	resp.Blocked = item.Blocked
+	return nil
+}
+
+// Set implements api.RulesHandler.
+func (s *Service) Set(ctx context.Context, req *api.RulesSetRequest, resp *api.RulesSetResponse) error {
+	id := model.ID(req.Id)
+	if err := req.Rules.Validate(); err != nil {
+		return err
+	}
+
+	item, err := s.Database.GetListItem(ctx, id)
+	if err != nil {
+		logger.Errorf("Get item %s failed: %s", id, err)
+		return err
+	}
+	if item == nil {
+		return errors.New("item not found")
+	}
+
+	if err = s.Database.UpdateListItemRules(ctx, id, req.Rules); err != nil {
+		logger.Errorf("Update rules of '%s' [ %s ] failed: %s", item.Title, id, err)
+		return err
+	}
+
+	logger.Infof("Selection rules of '%s' [ %s ] updated", item.Title, id)
+	return nil
+}
@@ -4,10 +4,15 @@ import (
 	"context"
 
 	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/metainfo"
 )
 
 func (s *Service) download(ctx context.Context, item *model.ListItem, torrentLink *string, content []byte) error {
 	var err error
+	if len(content) == 0 && metainfo.IsMagnet([]byte(*torrentLink)) {
+		// магнет-ссылка передается в rms-torrent как есть
+		content = []byte(*torrentLink)
+	}
 	if len(content) == 0 {
 		content, err = s.Movies.GetTorrentContent(ctx, *torrentLink)
 		if err != nil {
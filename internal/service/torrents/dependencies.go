@@ -4,11 +4,14 @@ import (
 	"context"
 
 	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/api"
 	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
 )
 
 type Database interface {
 	GetListItem(ctx context.Context, id model.ID) (*model.ListItem, error)
+	BlockTorrent(ctx context.Context, id model.ID, link string) error
+	BlockTorrentHash(ctx context.Context, id model.ID, infoHash string) error
 }
 
 type DownloadsManager interface {
@@ -19,4 +22,5 @@ type DownloadsManager interface {
 type Movies interface {
 	GetTorrentContent(ctx context.Context, torrentId string) ([]byte, error)
 	FindTorrents(ctx context.Context, id model.ID, season *uint) ([]*rms_library.Torrent, error)
+	ExplainTorrents(ctx context.Context, id model.ID, season *uint) ([]*api.RankedTorrent, error)
 }
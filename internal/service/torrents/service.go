@@ -8,6 +8,8 @@ import (
 
 	"github.com/RacoonMediaServer/rms-library/v3/internal/lock"
 	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/api"
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/metainfo"
 	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
 	"go-micro.dev/v4/logger"
 	"google.golang.org/protobuf/types/known/emptypb"
@@ -55,6 +57,16 @@ func (s *Service) Add(ctx context.Context, req *rms_library.TorrentsAddRequest,
 	if req.Link != nil && len(req.TorrentFile) != 0 {
 		return errors.New("ambigous content presented")
 	}
+	if len(req.TorrentFile) != 0 {
+		if _, err := metainfo.Load(req.TorrentFile); err != nil {
+			return err
+		}
+	}
+	if req.Link != nil && metainfo.IsMagnet([]byte(*req.Link)) {
+		if _, err := metainfo.ParseMagnet(*req.Link); err != nil {
+			return err
+		}
+	}
 
 	id := model.ID(req.Id)
 
@@ -85,6 +97,25 @@ func (s *Service) Delete(ctx context.Context, req *rms_library.TorrentsDeleteReq
 	}
 	defer lk.Unlock()
 
+	// удаленный вручную релиз больше не должен скачиваться автоматически
+	for i := range item.Torrents {
+		t := &item.Torrents[i]
+		if t.ID != req.TorrentId {
+			continue
+		}
+		if t.InfoHash != "" {
+			if err = s.Database.BlockTorrentHash(ctx, item.ID, t.InfoHash); err != nil {
+				logger.Warnf("Block torrent %s of '%s' [ %s ] failed: %s", req.TorrentId, item.Title, item.ID, err)
+			}
+		}
+		if t.Release != nil && t.Release.Link != "" {
+			if err = s.Database.BlockTorrent(ctx, item.ID, t.Release.Link); err != nil {
+				logger.Warnf("Block release %s of '%s' [ %s ] failed: %s", req.TorrentId, item.Title, item.ID, err)
+			}
+		}
+		break
+	}
+
 	if err = s.Downloads.RemoveTorrent(ctx, item, req.TorrentId); err != nil {
 		logger.Errorf("Remove torrent %s of '%s' [ %s ] failed: %s", req.TorrentId, item.Title, item.ID, err)
 		return err
@@ -118,6 +149,22 @@ func (s *Service) Find(ctx context.Context, req *rms_library.TorrentsFindRequest
 	return err
 }
 
+// Explain implements api.RankingHandler.
+func (s *Service) Explain(ctx context.Context, req *api.RankingExplainRequest, resp *api.RankingExplainResponse) error {
+	id := model.ID(req.Id)
+	var err error
+	switch id.ContentType() {
+	case rms_library.ContentType_TypeMovies:
+		resp.Torrents, err = s.Movies.ExplainTorrents(ctx, id, getSeasonPtr(req.Season))
+	default:
+		err = errors.New("unsupported content type")
+	}
+	if err != nil {
+		logger.Errorf("Explain torrents ranking for %s failed: %s", req.Id, err)
+	}
+	return err
+}
+
 // List implements rms_library.TorrentsHandler.
 func (s *Service) List(ctx context.Context, req *rms_library.TorrentsListRequest, resp *rms_library.TorrentsListResponse) error {
 	id := model.ID(req.Id)
@@ -1,15 +1,29 @@
 package storage
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 
-	"github.com/google/uuid"
+	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/metainfo"
 )
 
+// StoreArchiveTorrent saves the torrent file or magnet link to the archive. Files are named by infohash, so the same torrent is stored once
 func (m *Manager) StoreArchiveTorrent(itemTitle string, torrent []byte) (id string, err error) {
+	mi, err := metainfo.Load(torrent)
+	if err != nil {
+		return "", err
+	}
 	itemTitle = escape(itemTitle)
-	fileName := uuid.NewString() + ".torrent"
+	fileName := mi.InfoHash + ".torrent"
+	if mi.Magnet {
+		fileName = mi.InfoHash + ".magnet"
+		torrent = bytes.TrimSpace(torrent)
+	}
 	id = filepath.Join(itemTitle, fileName)
 	err = os.MkdirAll(filepath.Join(m.dirs.Archive, itemTitle), mediaPerms)
 	if err == nil {
@@ -21,3 +35,48 @@ func (m *Manager) StoreArchiveTorrent(itemTitle string, torrent []byte) (id stri
 func (m *Manager) LoadArchiveTorrent(contentPath string) ([]byte, error) {
 	return os.ReadFile(filepath.Join(m.dirs.Archive, contentPath))
 }
+
+// RemoveArchiveTorrent removes the file from the archive, the directory of the item is removed when it becomes empty
+func (m *Manager) RemoveArchiveTorrent(contentPath string) error {
+	fullPath := filepath.Join(m.dirs.Archive, contentPath)
+	if err := os.Remove(fullPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if dir := filepath.Dir(fullPath); dir != filepath.Clean(m.dirs.Archive) {
+		// непустую директорию os.Remove не удалит
+		_ = os.Remove(dir)
+	}
+	return nil
+}
+
+// ListArchiveTorrents returns all files stored in the archive
+func (m *Manager) ListArchiveTorrents() ([]model.ArchiveFile, error) {
+	var files []model.ArchiveFile
+	err := filepath.Walk(m.dirs.Archive, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		relpath, err := filepath.Rel(m.dirs.Archive, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, model.ArchiveFile{Path: relpath, Size: info.Size(), ModTime: info.ModTime()})
+		return nil
+	})
+	return files, err
+}
+
+// WriteArchiveTorrent writes the file to the archive as is, it is used to restore the archive from backup
+func (m *Manager) WriteArchiveTorrent(contentPath string, content []byte) error {
+	if !filepath.IsLocal(contentPath) {
+		return fmt.Errorf("invalid archive path: %s", contentPath)
+	}
+	fullPath := filepath.Join(m.dirs.Archive, contentPath)
+	if err := os.MkdirAll(filepath.Dir(fullPath), mediaPerms); err != nil {
+		return err
+	}
+	return os.WriteFile(fullPath, content, mediaPerms)
+}
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"io/fs"
+	"path/filepath"
+
+	"github.com/RacoonMediaServer/rms-library/v3/internal/analysis"
+	"github.com/RacoonMediaServer/rms-library/v3/internal/model"
+)
+
+// MoviesScanEpisodes walks through the torrent content and collects all recognized episodes
+func (m *Manager) MoviesScanEpisodes(t *model.TorrentRecord, numbering analysis.Numbering) ([]model.Episode, error) {
+	var episodes []model.Episode
+
+	err := filepath.Walk(t.Location, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		relpath, err := filepath.Rel(t.Location, path)
+		if err != nil {
+			return err
+		}
+		if relpath == "." {
+			relpath = info.Name()
+		}
+
+		for _, e := range analysis.RecognizeEpisodes(relpath, uint64(info.Size()), numbering) {
+			e.TorrentID = t.ID
+			episodes = append(episodes, e)
+		}
+		return nil
+	})
+
+	return episodes, err
+}
+
+// MoviesAnalyzeFiles analyzes names of the torrent files, the torrent directory is included to the paths
+func (m *Manager) MoviesAnalyzeFiles(t *model.TorrentRecord) ([]analysis.MediaFile, error) {
+	var files []analysis.MediaFile
+	root := filepath.Dir(t.Location)
+
+	err := filepath.Walk(t.Location, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		relpath, err := filepath.Rel(t.Location, path)
+		if err != nil {
+			return err
+		}
+		if relpath == "." {
+			relpath = info.Name()
+		}
+
+		// имя директории раздачи тоже содержит название и год
+		namedPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, analysis.MediaFile{Path: namedPath, Result: analysis.Analyze(namedPath)})
+		return nil
+	})
+
+	return files, err
+}
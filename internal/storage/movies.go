@@ -6,6 +6,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 	"unicode"
 	"unicode/utf8"
 
@@ -15,18 +16,30 @@ import (
 	"go-micro.dev/v4/logger"
 )
 
-const rawFilesDirectory = "_Raw"
+const (
+	rawFilesDirectory = "_Raw"
+	extrasDirectory   = "Extras"
+	specialsDirectory = "Specials"
+)
 
 type movieLayout struct {
 	root         string
 	l            logger.Logger
 	mi           *rms_library.MovieInfo
+	numbering    analysis.Numbering
 	t            *model.TorrentRecord
 	mapMovieDirs []string
+
+	// qualityMismatch is set when the quality of files differs from the search result
+	qualityMismatch bool
+
+	// linked are names of links which are already used by supply files
+	linked map[string]bool
 }
 
 // MoviesMountTorrent implements downloads.DirectoryManager.
-func (m *Manager) MoviesMountTorrent(mi *rms_library.MovieInfo, t *model.TorrentRecord) error {
+func (m *Manager) MoviesMountTorrent(mov *model.Movie, t *model.TorrentRecord) error {
+	mi := &mov.Info
 	l := logger.DefaultLogger.Fields(map[string]interface{}{
 		"title":   mi.Title,
 		"tid":     t.ID,
@@ -43,6 +56,7 @@ func (m *Manager) MoviesMountTorrent(mi *rms_library.MovieInfo, t *model.Torrent
 		root:         m.dirs.Content,
 		l:            l,
 		mi:           mi,
+		numbering:    analysis.NewNumbering(mov),
 		t:            t,
 		mapMovieDirs: mapMovieDirectories(mi, t.Title),
 	}
@@ -57,7 +71,8 @@ func (m *Manager) MoviesMountTorrent(mi *rms_library.MovieInfo, t *model.Torrent
 }
 
 // MoviesUmountTorrent implements downloads.DirectoryManager.
-func (m *Manager) MoviesUmountTorrent(mi *rms_library.MovieInfo, t *model.TorrentRecord) {
+func (m *Manager) MoviesUmountTorrent(mov *model.Movie, t *model.TorrentRecord) {
+	mi := &mov.Info
 	l := logger.DefaultLogger.Fields(map[string]interface{}{
 		"title":   mi.Title,
 		"tid":     t.ID,
@@ -97,6 +112,10 @@ func mapMovieDirectories(mi *rms_library.MovieInfo, torrentTitle string) (direct
 
 func (ml *movieLayout) mount() {
 	originDir := ml.t.Location
+	ml.linked = map[string]bool{}
+
+	var videos, supplies []analysis.MediaFile
+	var targets []string
 
 	err := filepath.Walk(originDir, func(path string, info fs.FileInfo, err error) error {
 		if err != nil {
@@ -112,8 +131,19 @@ func (ml *movieLayout) mount() {
 			return err
 		}
 		mediaInfo := analysis.Analyze(relpath)
+		mediaInfo.ApplyNumbering(ml.numbering)
+		ml.verifyQuality(relpath, &mediaInfo)
 
-		ml.makeFileLinks(path, relpath, mediaInfo)
+		f := analysis.MediaFile{Path: relpath, Result: mediaInfo}
+		if mediaInfo.FileType == model.FileTypeMediaSupply {
+			supplies = append(supplies, f)
+			return nil
+		}
+		target := ml.makeFileLinks(path, relpath, mediaInfo)
+		if target != "" && isVideo(mediaInfo.FileType) {
+			videos = append(videos, f)
+			targets = append(targets, target)
+		}
 
 		return nil
 	})
@@ -122,22 +152,103 @@ func (ml *movieLayout) mount() {
 		ml.l.Logf(logger.ErrorLevel, "Iterate directory '%s' failed: %s", originDir, err)
 		return
 	}
+
+	// субтитры и внешние дорожки кладем рядом с видео, к которому они относятся
+	for _, f := range supplies {
+		ml.makeSupplyLinks(f, videos, targets)
+	}
 }
 
-func (ml *movieLayout) makeFileLinks(path, relpath string, result analysis.Result) {
+func (ml *movieLayout) makeSupplyLinks(f analysis.MediaFile, videos []analysis.MediaFile, targets []string) {
+	origin := filepath.Join(ml.t.Location, f.Path)
 	if ml.mi.Type != rms_library.MovieType_TvSeries {
-		ml.makeLinks(path, relpath)
+		ml.makeLinks(origin, f.Path)
+	} else {
+		ml.makeLinks(origin, filepath.Join(rawFilesDirectory, f.Path))
+	}
+
+	i := analysis.MatchSupply(f, videos)
+	if i < 0 {
+		ml.l.Logf(logger.DebugLevel, "Video for '%s' not found", f.Path)
 		return
 	}
 
-	ml.makeLinks(path, filepath.Join(rawFilesDirectory, relpath))
-	if result.Episode == 0 || result.Season == 0 {
+	target := composeSupplyFileName(targets[i], f.Path, f.Result.Language)
+	for n := 2; ml.linked[target]; n++ {
+		target = composeSupplyFileName(targets[i], f.Path, fmt.Sprintf("%s.%d", f.Result.Language, n))
+	}
+	ml.linked[target] = true
+
+	if target != f.Path {
+		ml.makeLinks(origin, target)
+	}
+}
+
+func isVideo(fileType model.FileType) bool {
+	return fileType == model.FileTypeFilm || fileType == model.FileTypeEpisode || fileType == model.FileTypeSpecial
+}
+
+// verifyQuality compares quality of the video file with the search result which the torrent was downloaded from
+func (ml *movieLayout) verifyQuality(relpath string, result *analysis.Result) {
+	if ml.qualityMismatch || ml.t.Release == nil || ml.t.Release.Quality == "" || result.Tags.Quality == "" {
+		return
+	}
+	if result.FileType != model.FileTypeFilm && result.FileType != model.FileTypeEpisode {
 		return
 	}
+	if !strings.EqualFold(result.Tags.Quality, ml.t.Release.Quality) {
+		ml.qualityMismatch = true
+		ml.l.Logf(logger.WarnLevel, "Quality of '%s' is %s, but %s expected by search result", relpath, result.Tags.Quality, ml.t.Release.Quality)
+	}
+}
+
+// makeFileLinks creates links of the file and returns the main one, which is used for supply files
+func (ml *movieLayout) makeFileLinks(path, relpath string, result analysis.Result) string {
+	switch result.FileType {
+	case model.FileTypeSample:
+		return ""
+	case model.FileTypeTrailer, model.FileTypeExtra:
+		ml.makeLinks(path, filepath.Join(extrasDirectory, filepath.Base(path)))
+		return ""
+	}
+
+	if ml.mi.Type != rms_library.MovieType_TvSeries {
+		ml.makeLinks(path, relpath)
+		if result.FileType != model.FileTypeFilm {
+			return ""
+		}
+		return relpath
+	}
+
+	ml.makeLinks(path, filepath.Join(rawFilesDirectory, relpath))
+	if result.FileType == model.FileTypeSpecial {
+		fName := filepath.Base(path)
+		if result.Episode > 0 {
+			fName = composeMovieFileName(ml.mi, path, &result)
+		}
+		target := filepath.Join(specialsDirectory, fName)
+		ml.makeLinks(path, target)
+		return target
+	}
+	if !result.AirDate.IsZero() {
+		// ежедневные шоу без метаданных раскладываются по году выпуска
+		season := result.Season
+		if season == 0 {
+			season = uint(result.AirDate.Year())
+		}
+		seasonDir := fmt.Sprintf("Сезон %d", season)
+		target := filepath.Join(seasonDir, composeMovieFileName(ml.mi, path, &result))
+		ml.makeLinks(path, target)
+		return target
+	}
+	if result.Episode == 0 || result.Season == 0 || result.FileType != model.FileTypeEpisode {
+		return ""
+	}
 
 	seasonDir := fmt.Sprintf("Сезон %d", result.Season)
-	fName := composeMovieFileName(ml.mi, path, &result)
-	ml.makeLinks(path, filepath.Join(seasonDir, fName))
+	target := filepath.Join(seasonDir, composeMovieFileName(ml.mi, path, &result))
+	ml.makeLinks(path, target)
+	return target
 }
 
 func (ml *movieLayout) makeLink(origin, target string) {
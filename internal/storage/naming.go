@@ -3,6 +3,8 @@ package storage
 import (
 	"fmt"
 	"path"
+	"strings"
+	"time"
 
 	"github.com/RacoonMediaServer/rms-library/v3/internal/analysis"
 	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
@@ -28,21 +30,44 @@ func composeMovieFileName(mi *rms_library.MovieInfo, fullPath string, info *anal
 		}
 		return escape(info.EpisodeName) + ext
 	case rms_library.MovieType_TvSeries:
+		if !info.AirDate.IsZero() {
+			if info.EpisodeName == "" {
+				return info.AirDate.Format(time.DateOnly) + ext
+			}
+			return info.AirDate.Format(time.DateOnly) + ". " + fileName
+		}
 		if info.Episode < 0 {
 			if info.EpisodeName == "" {
 				return fileName
 			}
 			return info.EpisodeName + ext
 		}
+		episodes := fmt.Sprintf("E%02d", info.Episode)
+		if first, last := info.EpisodeRange(); last > first {
+			episodes = fmt.Sprintf("E%02d-E%02d", first, last)
+		}
 		if info.EpisodeName == "" {
-			return fmt.Sprintf("E%02d%s", info.Episode, ext)
+			if info.Tags.Quality != "" {
+				// качество в имени различает эпизоды разных раздач
+				return episodes + " [" + info.Tags.Quality + "]" + ext
+			}
+			return episodes + ext
 		}
-		return fmt.Sprintf("E%02d. %s", info.Episode, fileName)
+		return episodes + ". " + fileName
 	}
 
 	return ""
 }
 
+// composeSupplyFileName makes name of subtitles or audio track by the video link: <episode>.<lang>.<ext>
+func composeSupplyFileName(videoTarget, supplyPath, lang string) string {
+	name := strings.TrimSuffix(videoTarget, path.Ext(videoTarget))
+	if lang != "" {
+		name += "." + lang
+	}
+	return name + strings.ToLower(path.Ext(supplyPath))
+}
+
 func getMovieCategoryDir(mi *rms_library.MovieInfo) string {
 	switch mi.Type {
 	case rms_library.MovieType_TvSeries:
@@ -0,0 +1,27 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComposeSupplyFileName(t *testing.T) {
+	type testCase struct {
+		video  string
+		supply string
+		lang   string
+		result string
+	}
+
+	testCases := []testCase{
+		{video: "Season 1/E01.mkv", supply: "Subs/Show.S01E01.srt", lang: "rus", result: "Season 1/E01.rus.srt"},
+		{video: "Season 1/E01.mkv", supply: "Show.S01E01.SRT", lang: "", result: "Season 1/E01.srt"},
+		{video: "Dune (2021).mkv", supply: "Audio/Dune.2021.eng.AC3", lang: "eng", result: "Dune (2021).eng.ac3"},
+		{video: "E01 [1080p].mkv", supply: "E01.ass", lang: "eng", result: "E01 [1080p].eng.ass"},
+	}
+
+	for i, tc := range testCases {
+		assert.Equal(t, tc.result, composeSupplyFileName(tc.video, tc.supply, tc.lang), "Test %d failed", i)
+	}
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+
+	"go-micro.dev/v4/client"
+	"go-micro.dev/v4/server"
+)
+
+type AnalyzerTraceRequest struct {
+	Path string
+}
+
+// AnalyzerToken describes how a single token of the name was handled
+type AnalyzerToken struct {
+	Text     string
+	InBraces bool
+	Removed  bool
+	Rule     string
+}
+
+// AnalyzerLayer is a token-level trace of one path component
+type AnalyzerLayer struct {
+	Name   string
+	Input  string
+	Tokens []AnalyzerToken
+	Title  string
+}
+
+type AnalyzerTraceResponse struct {
+	Layers      []AnalyzerLayer
+	Titles      []string
+	Year        uint32
+	FileType    int32
+	Season      uint32
+	Episode     int32
+	LastEpisode int32
+	Absolute    bool
+	AirDate     string
+}
+
+// AnalyzerService is a client of file names analyzer debug API
+type AnalyzerService interface {
+	// Trace returns token-level trace of the file name analysis
+	Trace(ctx context.Context, in *AnalyzerTraceRequest, opts ...client.CallOption) (*AnalyzerTraceResponse, error)
+}
+
+type analyzerService struct {
+	c    client.Client
+	name string
+}
+
+// NewAnalyzerService creates a client of file names analyzer debug API
+func NewAnalyzerService(name string, c client.Client) AnalyzerService {
+	return &analyzerService{c: c, name: name}
+}
+
+func (s *analyzerService) Trace(ctx context.Context, in *AnalyzerTraceRequest, opts ...client.CallOption) (*AnalyzerTraceResponse, error) {
+	return call[AnalyzerTraceRequest, AnalyzerTraceResponse](ctx, s.c, s.name, "Analyzer.Trace", in, opts...)
+}
+
+// AnalyzerHandler is a server side of file names analyzer debug API
+type AnalyzerHandler interface {
+	// Trace returns token-level trace of the file name analysis
+	Trace(ctx context.Context, req *AnalyzerTraceRequest, resp *AnalyzerTraceResponse) error
+}
+
+// Analyzer is an endpoint name holder for AnalyzerHandler
+type Analyzer struct {
+	AnalyzerHandler
+}
+
+// RegisterAnalyzerHandler registers file names analyzer debug API handler
+func RegisterAnalyzerHandler(s server.Server, h AnalyzerHandler, opts ...server.HandlerOption) error {
+	return register(s, &Analyzer{h}, opts...)
+}
@@ -0,0 +1,23 @@
+// Package api contains the extended rms-library API, which is not covered by rms-packages protocol.
+// Messages are plain structures, so the API must be called with JSON codec (go-micro client default).
+package api
+
+import (
+	"context"
+
+	"go-micro.dev/v4/client"
+	"go-micro.dev/v4/server"
+)
+
+func call[Req any, Resp any](ctx context.Context, c client.Client, service, endpoint string, in *Req, opts ...client.CallOption) (*Resp, error) {
+	req := c.NewRequest(service, endpoint, in)
+	out := new(Resp)
+	if err := c.Call(ctx, req, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func register(s server.Server, handler interface{}, opts ...server.HandlerOption) error {
+	return s.Handle(s.NewHandler(handler, opts...))
+}
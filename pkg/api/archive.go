@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+
+	"go-micro.dev/v4/client"
+	"go-micro.dev/v4/server"
+)
+
+type ArchiveStatsRequest struct {
+}
+
+type ArchiveStatsResponse struct {
+	// Files is a total count of stored torrent files
+	Files  uint32
+	SizeMB uint64
+
+	// Orphans are files, which are not referenced by any item
+	Orphans        uint32
+	OrphansSizeMB  uint64
+	OrphansExample []string
+
+	// Missing are paths, which are referenced by items, but absent in the archive
+	Missing uint32
+}
+
+type ArchiveCleanupRequest struct {
+	// DryRun means orphans are only reported, not removed
+	DryRun bool
+}
+
+type ArchiveCleanupResponse struct {
+	Removed []string
+	FreedMB uint64
+}
+
+// ArchiveService is a client of the torrent files archive API
+type ArchiveService interface {
+	// Stats returns size of the archive and counts of orphaned files
+	Stats(ctx context.Context, in *ArchiveStatsRequest, opts ...client.CallOption) (*ArchiveStatsResponse, error)
+
+	// Cleanup removes orphaned files from the archive
+	Cleanup(ctx context.Context, in *ArchiveCleanupRequest, opts ...client.CallOption) (*ArchiveCleanupResponse, error)
+}
+
+type archiveService struct {
+	c    client.Client
+	name string
+}
+
+// NewArchiveService creates a client of the torrent files archive API
+func NewArchiveService(name string, c client.Client) ArchiveService {
+	return &archiveService{c: c, name: name}
+}
+
+func (s *archiveService) Stats(ctx context.Context, in *ArchiveStatsRequest, opts ...client.CallOption) (*ArchiveStatsResponse, error) {
+	return call[ArchiveStatsRequest, ArchiveStatsResponse](ctx, s.c, s.name, "Archive.Stats", in, opts...)
+}
+
+func (s *archiveService) Cleanup(ctx context.Context, in *ArchiveCleanupRequest, opts ...client.CallOption) (*ArchiveCleanupResponse, error) {
+	return call[ArchiveCleanupRequest, ArchiveCleanupResponse](ctx, s.c, s.name, "Archive.Cleanup", in, opts...)
+}
+
+// ArchiveHandler is a server side of the torrent files archive API
+type ArchiveHandler interface {
+	// Stats returns size of the archive and counts of orphaned files
+	Stats(ctx context.Context, req *ArchiveStatsRequest, resp *ArchiveStatsResponse) error
+
+	// Cleanup removes orphaned files from the archive
+	Cleanup(ctx context.Context, req *ArchiveCleanupRequest, resp *ArchiveCleanupResponse) error
+}
+
+// Archive is an endpoint name holder for ArchiveHandler
+type Archive struct {
+	ArchiveHandler
+}
+
+// RegisterArchiveHandler registers the torrent files archive API handler
+func RegisterArchiveHandler(s server.Server, h ArchiveHandler, opts ...server.HandlerOption) error {
+	return register(s, &Archive{h}, opts...)
+}
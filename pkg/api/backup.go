@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+
+	"go-micro.dev/v4/client"
+	"go-micro.dev/v4/server"
+)
+
+// Conflict resolution modes of import
+const (
+	// ImportSkip keeps existing items untouched
+	ImportSkip = "skip"
+
+	// ImportReplace overwrites existing items
+	ImportReplace = "replace"
+
+	// ImportMerge adds missing archive candidates, blocked torrents and preferences to existing items
+	ImportMerge = "merge"
+)
+
+// MaxInlineBackupSize limits the backup passed inside requests and responses, larger backups must be passed through files
+const MaxInlineBackupSize = 4 * 1024 * 1024
+
+type BackupExportRequest struct {
+	// Path is a file on the server side, a new file in the backup directory is created if empty
+	Path string
+
+	// Inline means the backup is returned in the response instead of the file, it is limited by MaxInlineBackupSize
+	Inline bool
+}
+
+type BackupExportResponse struct {
+	// Path is a file on the server side, which the backup is saved to
+	Path string
+
+	// Data is a tar archive with the library, presented only if Inline is requested
+	Data  []byte
+	Items uint32
+	Files uint32
+}
+
+type BackupImportRequest struct {
+	// Path is a file on the server side, Data is used if empty. Data is limited by MaxInlineBackupSize
+	Path string
+	Data []byte
+
+	// Conflict is a mode of handling items, which already exist: skip (default), replace or merge
+	Conflict string
+}
+
+type BackupImportResponse struct {
+	Added    uint32
+	Replaced uint32
+	Merged   uint32
+	Skipped  uint32
+	Files    uint32
+}
+
+// BackupService is a client of the library export and import API
+type BackupService interface {
+	// Export saves all items of the library and the archive of torrent files to the tar archive
+	Export(ctx context.Context, in *BackupExportRequest, opts ...client.CallOption) (*BackupExportResponse, error)
+
+	// Import restores the library from the tar archive
+	Import(ctx context.Context, in *BackupImportRequest, opts ...client.CallOption) (*BackupImportResponse, error)
+}
+
+type backupService struct {
+	c    client.Client
+	name string
+}
+
+// NewBackupService creates a client of the library export and import API
+func NewBackupService(name string, c client.Client) BackupService {
+	return &backupService{c: c, name: name}
+}
+
+func (s *backupService) Export(ctx context.Context, in *BackupExportRequest, opts ...client.CallOption) (*BackupExportResponse, error) {
+	return call[BackupExportRequest, BackupExportResponse](ctx, s.c, s.name, "Backup.Export", in, opts...)
+}
+
+func (s *backupService) Import(ctx context.Context, in *BackupImportRequest, opts ...client.CallOption) (*BackupImportResponse, error) {
+	return call[BackupImportRequest, BackupImportResponse](ctx, s.c, s.name, "Backup.Import", in, opts...)
+}
+
+// BackupHandler is a server side of the library export and import API
+type BackupHandler interface {
+	// Export saves all items of the library and the archive of torrent files to the tar archive
+	Export(ctx context.Context, req *BackupExportRequest, resp *BackupExportResponse) error
+
+	// Import restores the library from the tar archive
+	Import(ctx context.Context, req *BackupImportRequest, resp *BackupImportResponse) error
+}
+
+// Backup is an endpoint name holder for BackupHandler
+type Backup struct {
+	BackupHandler
+}
+
+// RegisterBackupHandler registers the library export and import API handler
+func RegisterBackupHandler(s server.Server, h BackupHandler, opts ...server.HandlerOption) error {
+	return register(s, &Backup{h}, opts...)
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"context"
+
+	"go-micro.dev/v4/client"
+	"go-micro.dev/v4/server"
+)
+
+// Episode is a downloaded episode of TV series
+type Episode struct {
+	Season  uint32
+	No      uint32
+	Path    string
+	Size    uint64
+	Torrent string
+	Quality string
+}
+
+// Season is a summary of downloaded episodes of the season
+type Season struct {
+	No       uint32
+	Episodes []uint32
+	Missing  []uint32
+
+	// Pending are episodes of added torrents, which are not downloaded yet
+	Pending []uint32
+}
+
+type EpisodesListRequest struct {
+	Id string
+}
+
+type EpisodesListResponse struct {
+	Seasons  []*Season
+	Episodes []*Episode
+
+	// SeasonLengths are episode counts of seasons used for absolute numbering
+	SeasonLengths []uint32
+
+	// AirDates maps air dates (YYYY-MM-DD) of daily show to the episodes
+	AirDates map[string]*Episode
+}
+
+type EpisodesSetNumberingRequest struct {
+	Id string
+
+	// SeasonLengths are episode counts of seasons, which are used to convert absolute episode numbers (anime)
+	SeasonLengths []uint32
+
+	// AirDates maps air dates (YYYY-MM-DD) of daily show to the episodes. Only Season and No of the episode are used
+	AirDates map[string]*Episode
+}
+
+type EpisodesSetNumberingResponse struct {
+}
+
+// EpisodesService is a client of episodes inventory API
+type EpisodesService interface {
+	// List returns episodes inventory of the series
+	List(ctx context.Context, in *EpisodesListRequest, opts ...client.CallOption) (*EpisodesListResponse, error)
+
+	// SetNumbering sets episode counts of seasons and air dates for absolute numbered series and daily shows
+	SetNumbering(ctx context.Context, in *EpisodesSetNumberingRequest, opts ...client.CallOption) (*EpisodesSetNumberingResponse, error)
+}
+
+type episodesService struct {
+	c    client.Client
+	name string
+}
+
+// NewEpisodesService creates a client of episodes inventory API
+func NewEpisodesService(name string, c client.Client) EpisodesService {
+	return &episodesService{c: c, name: name}
+}
+
+func (s *episodesService) List(ctx context.Context, in *EpisodesListRequest, opts ...client.CallOption) (*EpisodesListResponse, error) {
+	return call[EpisodesListRequest, EpisodesListResponse](ctx, s.c, s.name, "Episodes.List", in, opts...)
+}
+
+func (s *episodesService) SetNumbering(ctx context.Context, in *EpisodesSetNumberingRequest, opts ...client.CallOption) (*EpisodesSetNumberingResponse, error) {
+	return call[EpisodesSetNumberingRequest, EpisodesSetNumberingResponse](ctx, s.c, s.name, "Episodes.SetNumbering", in, opts...)
+}
+
+// EpisodesHandler is a server side of episodes inventory API
+type EpisodesHandler interface {
+	// List returns episodes inventory of the series
+	List(ctx context.Context, req *EpisodesListRequest, resp *EpisodesListResponse) error
+
+	// SetNumbering sets episode counts of seasons and air dates for absolute numbered series and daily shows
+	SetNumbering(ctx context.Context, req *EpisodesSetNumberingRequest, resp *EpisodesSetNumberingResponse) error
+}
+
+// Episodes is an endpoint name holder for EpisodesHandler
+type Episodes struct {
+	EpisodesHandler
+}
+
+// RegisterEpisodesHandler registers episodes inventory API handler
+func RegisterEpisodesHandler(s server.Server, h EpisodesHandler, opts ...server.HandlerOption) error {
+	return register(s, &Episodes{h}, opts...)
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+
+	"go-micro.dev/v4/client"
+	"go-micro.dev/v4/server"
+)
+
+// Score is a rank of the torrent by the single criterion
+type Score struct {
+	Name   string
+	Weight float32
+	Rank   float32
+}
+
+// RankedTorrent is a found torrent with breakdown of the selector rank
+type RankedTorrent struct {
+	Id      string
+	Title   string
+	Size    uint64
+	Seeders uint32
+	Quality string
+	Voice   string
+	Rank    float32
+	Scores  []*Score
+}
+
+type RankingExplainRequest struct {
+	Id     string
+	Season *uint32
+}
+
+type RankingExplainResponse struct {
+	Torrents []*RankedTorrent
+}
+
+// RankingService is a client of torrents ranking API
+type RankingService interface {
+	// Explain searches torrents for the item and returns them sorted by rank with the rank breakdown
+	Explain(ctx context.Context, in *RankingExplainRequest, opts ...client.CallOption) (*RankingExplainResponse, error)
+}
+
+type rankingService struct {
+	c    client.Client
+	name string
+}
+
+// NewRankingService creates a client of torrents ranking API
+func NewRankingService(name string, c client.Client) RankingService {
+	return &rankingService{c: c, name: name}
+}
+
+func (s *rankingService) Explain(ctx context.Context, in *RankingExplainRequest, opts ...client.CallOption) (*RankingExplainResponse, error) {
+	return call[RankingExplainRequest, RankingExplainResponse](ctx, s.c, s.name, "Ranking.Explain", in, opts...)
+}
+
+// RankingHandler is a server side of torrents ranking API
+type RankingHandler interface {
+	// Explain searches torrents for the item and returns them sorted by rank with the rank breakdown
+	Explain(ctx context.Context, req *RankingExplainRequest, resp *RankingExplainResponse) error
+}
+
+// Ranking is an endpoint name holder for RankingHandler
+type Ranking struct {
+	RankingHandler
+}
+
+// RegisterRankingHandler registers torrents ranking API handler
+func RegisterRankingHandler(s server.Server, h RankingHandler, opts ...server.HandlerOption) error {
+	return register(s, &Ranking{h}, opts...)
+}
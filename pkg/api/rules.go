@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/selector"
+	"go-micro.dev/v4/client"
+	"go-micro.dev/v4/server"
+)
+
+type RulesGetRequest struct {
+	Id string
+}
+
+type RulesGetResponse struct {
+	Rules   selector.Rules
+	Blocked []string
+}
+
+type RulesSetRequest struct {
+	Id    string
+	Rules selector.Rules
+}
+
+type RulesSetResponse struct {
+}
+
+// RulesService is a client of torrents selection rules API
+type RulesService interface {
+	// Get returns item specific selection rules
+	Get(ctx context.Context, in *RulesGetRequest, opts ...client.CallOption) (*RulesGetResponse, error)
+
+	// Set replaces item specific selection rules
+	Set(ctx context.Context, in *RulesSetRequest, opts ...client.CallOption) (*RulesSetResponse, error)
+}
+
+type rulesService struct {
+	c    client.Client
+	name string
+}
+
+// NewRulesService creates a client of torrents selection rules API
+func NewRulesService(name string, c client.Client) RulesService {
+	return &rulesService{c: c, name: name}
+}
+
+func (s *rulesService) Get(ctx context.Context, in *RulesGetRequest, opts ...client.CallOption) (*RulesGetResponse, error) {
+	return call[RulesGetRequest, RulesGetResponse](ctx, s.c, s.name, "Rules.Get", in, opts...)
+}
+
+func (s *rulesService) Set(ctx context.Context, in *RulesSetRequest, opts ...client.CallOption) (*RulesSetResponse, error) {
+	return call[RulesSetRequest, RulesSetResponse](ctx, s.c, s.name, "Rules.Set", in, opts...)
+}
+
+// RulesHandler is a server side of torrents selection rules API
+type RulesHandler interface {
+	// Get returns item specific selection rules
+	Get(ctx context.Context, req *RulesGetRequest, resp *RulesGetResponse) error
+
+	// Set replaces item specific selection rules
+	Set(ctx context.Context, req *RulesSetRequest, resp *RulesSetResponse) error
+}
+
+// Rules is an endpoint name holder for RulesHandler
+type Rules struct {
+	RulesHandler
+}
+
+// RegisterRulesHandler registers torrents selection rules API handler
+func RegisterRulesHandler(s server.Server, h RulesHandler, opts ...server.HandlerOption) error {
+	return register(s, &Rules{h}, opts...)
+}
@@ -0,0 +1,178 @@
+// Package bencode implements decoding of bencoded data (BEP 3).
+// Values are decoded to int64, string, []any and map[string]any
+package bencode
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+var ErrInvalidData = errors.New("invalid bencoded data")
+
+const maxDepth = 64
+
+type decoder struct {
+	data []byte
+	pos  int
+
+	// spans are raw bytes of values of the top level dictionary
+	spans map[string][]byte
+	depth int
+}
+
+// Decode decodes the whole data as a single bencoded value
+func Decode(data []byte) (any, error) {
+	d := decoder{data: data}
+	v, err := d.decode()
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(d.data) {
+		return nil, fmt.Errorf("%w: trailing data at %d", ErrInvalidData, d.pos)
+	}
+	return v, nil
+}
+
+// DecodeDict decodes the top level dictionary and returns raw bytes of each its value as well
+func DecodeDict(data []byte) (map[string]any, map[string][]byte, error) {
+	d := decoder{data: data, spans: map[string][]byte{}}
+	v, err := d.decode()
+	if err != nil {
+		return nil, nil, err
+	}
+	if d.pos != len(d.data) {
+		return nil, nil, fmt.Errorf("%w: trailing data at %d", ErrInvalidData, d.pos)
+	}
+	dict, ok := v.(map[string]any)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: dictionary expected", ErrInvalidData)
+	}
+	return dict, d.spans, nil
+}
+
+func (d *decoder) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: %s at %d", ErrInvalidData, fmt.Sprintf(format, args...), d.pos)
+}
+
+func (d *decoder) decode() (any, error) {
+	if d.pos >= len(d.data) {
+		return nil, d.errorf("unexpected end")
+	}
+	if d.depth >= maxDepth {
+		return nil, d.errorf("too deep nesting")
+	}
+
+	switch c := d.data[d.pos]; {
+	case c == 'i':
+		return d.decodeInt()
+	case c == 'l':
+		return d.decodeList()
+	case c == 'd':
+		return d.decodeDict()
+	case c >= '0' && c <= '9':
+		return d.decodeString()
+	default:
+		return nil, d.errorf("unexpected symbol '%c'", c)
+	}
+}
+
+func (d *decoder) readUntil(end byte) (string, error) {
+	for i := d.pos; i < len(d.data); i++ {
+		if d.data[i] == end {
+			s := string(d.data[d.pos:i])
+			d.pos = i + 1
+			return s, nil
+		}
+	}
+	return "", d.errorf("'%c' expected", end)
+}
+
+func (d *decoder) decodeInt() (any, error) {
+	d.pos++
+	s, err := d.readUntil('e')
+	if err != nil {
+		return nil, err
+	}
+	if s == "" || s == "-" || s == "-0" || (len(s) > 1 && s[0] == '0') || (len(s) > 2 && s[0] == '-' && s[1] == '0') {
+		return nil, d.errorf("malformed integer '%s'", s)
+	}
+	val, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return nil, d.errorf("malformed integer '%s'", s)
+	}
+	return val, nil
+}
+
+func (d *decoder) decodeString() (string, error) {
+	s, err := d.readUntil(':')
+	if err != nil {
+		return "", err
+	}
+	length, err := strconv.Atoi(s)
+	if err != nil || length < 0 || (len(s) > 1 && s[0] == '0') {
+		return "", d.errorf("malformed string length '%s'", s)
+	}
+	if length > len(d.data)-d.pos {
+		return "", d.errorf("string is out of data")
+	}
+	str := string(d.data[d.pos : d.pos+length])
+	d.pos += length
+	return str, nil
+}
+
+func (d *decoder) decodeList() (any, error) {
+	d.pos++
+	d.depth++
+	defer func() { d.depth-- }()
+
+	list := []any{}
+	for {
+		if d.pos >= len(d.data) {
+			return nil, d.errorf("unterminated list")
+		}
+		if d.data[d.pos] == 'e' {
+			d.pos++
+			return list, nil
+		}
+		v, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+}
+
+func (d *decoder) decodeDict() (any, error) {
+	topLevel := d.depth == 0
+	d.pos++
+	d.depth++
+	defer func() { d.depth-- }()
+
+	dict := map[string]any{}
+	for {
+		if d.pos >= len(d.data) {
+			return nil, d.errorf("unterminated dictionary")
+		}
+		if d.data[d.pos] == 'e' {
+			d.pos++
+			return dict, nil
+		}
+		if c := d.data[d.pos]; c < '0' || c > '9' {
+			return nil, d.errorf("dictionary key must be a string")
+		}
+		key, err := d.decodeString()
+		if err != nil {
+			return nil, err
+		}
+		start := d.pos
+		v, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		if topLevel && d.spans != nil {
+			d.spans[key] = d.data[start:d.pos]
+		}
+		dict[key] = v
+	}
+}
@@ -0,0 +1,53 @@
+package bencode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecode(t *testing.T) {
+	type testCase struct {
+		input  string
+		output any
+		ok     bool
+	}
+
+	testCases := []testCase{
+		{input: "i42e", output: int64(42), ok: true},
+		{input: "i-3e", output: int64(-3), ok: true},
+		{input: "4:spam", output: "spam", ok: true},
+		{input: "0:", output: "", ok: true},
+		{input: "l4:spami7ee", output: []any{"spam", int64(7)}, ok: true},
+		{input: "d3:cow3:moo4:spaml1:a1:bee", output: map[string]any{"cow": "moo", "spam": []any{"a", "b"}}, ok: true},
+		{input: "", ok: false},
+		{input: "i03e", ok: false},
+		{input: "i-0e", ok: false},
+		{input: "ie", ok: false},
+		{input: "5:spam", ok: false},
+		{input: "l4:spam", ok: false},
+		{input: "di1e3:mooe", ok: false},
+		{input: "4:spamjunk", ok: false},
+		{input: "<html>", ok: false},
+	}
+
+	for _, tc := range testCases {
+		out, err := Decode([]byte(tc.input))
+		if tc.ok {
+			assert.NoError(t, err, tc.input)
+			assert.Equal(t, tc.output, out, tc.input)
+		} else {
+			assert.ErrorIs(t, err, ErrInvalidData, tc.input)
+		}
+	}
+}
+
+func TestDecodeDict(t *testing.T) {
+	dict, spans, err := DecodeDict([]byte("d8:announce3:url4:infod4:name4:file6:lengthi10eee"))
+	assert.NoError(t, err)
+	assert.Equal(t, "url", dict["announce"])
+	assert.Equal(t, "d4:name4:file6:lengthi10ee", string(spans["info"]))
+
+	_, _, err = DecodeDict([]byte("l1:ae"))
+	assert.Error(t, err)
+}
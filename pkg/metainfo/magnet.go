@@ -0,0 +1,82 @@
+package metainfo
+
+import (
+	"bytes"
+	"encoding/base32"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const magnetScheme = "magnet:"
+
+// IsMagnet checks the content is a magnet link
+func IsMagnet(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) >= len(magnetScheme) && strings.EqualFold(string(data[:len(magnetScheme)]), magnetScheme)
+}
+
+// ParseMagnet parses and validates the magnet link (BEP 9). File list of magnet is unknown until metadata is fetched
+func ParseMagnet(uri string) (*MetaInfo, error) {
+	uri = strings.TrimSpace(uri)
+	if !IsMagnet([]byte(uri)) {
+		return nil, invalid("magnet link expected")
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, invalid("malformed magnet link: %s", err)
+	}
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return nil, invalid("malformed magnet link: %s", err)
+	}
+
+	mi := MetaInfo{Magnet: true}
+	for _, xt := range query["xt"] {
+		if hash, ok := parseExactTopic(xt); ok {
+			mi.InfoHash = hash
+			break
+		}
+	}
+	if mi.InfoHash == "" {
+		return nil, invalid("btih is missing in magnet link")
+	}
+
+	mi.Name = query.Get("dn")
+	if trackers := query["tr"]; len(trackers) != 0 {
+		mi.Announce = trackers[0]
+	}
+	if size, err := strconv.ParseInt(query.Get("xl"), 10, 64); err == nil && size > 0 {
+		mi.Size = size
+	}
+
+	return &mi, nil
+}
+
+func parseExactTopic(xt string) (string, bool) {
+	const prefix = "urn:btih:"
+	if len(xt) <= len(prefix) || !strings.EqualFold(xt[:len(prefix)], prefix) {
+		return "", false
+	}
+	hash := xt[len(prefix):]
+	switch len(hash) {
+	case 40:
+		if _, err := hex.DecodeString(hash); err == nil {
+			return strings.ToLower(hash), true
+		}
+	case 32:
+		if raw, err := base32.StdEncoding.DecodeString(strings.ToUpper(hash)); err == nil {
+			return hex.EncodeToString(raw), true
+		}
+	}
+	return "", false
+}
+
+// Load parses the torrent content: .torrent file or magnet link
+func Load(data []byte) (*MetaInfo, error) {
+	if IsMagnet(data) {
+		return ParseMagnet(string(data))
+	}
+	return Parse(data)
+}
@@ -0,0 +1,124 @@
+// Package metainfo parses .torrent files (BEP 3) and magnet links (BEP 9): infohash, name, file list and size
+package metainfo
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/bencode"
+)
+
+var ErrInvalidTorrent = errors.New("invalid torrent file")
+
+// File is a file of the torrent
+type File struct {
+	// Path is a path of the file relative to the torrent root ('/' separated). For single file torrent it's the name
+	Path string
+	Size int64
+}
+
+// MetaInfo is a parsed .torrent file
+type MetaInfo struct {
+	InfoHash string
+	Name     string
+	Announce string
+	Private  bool
+	Files    []File
+	Size     int64
+
+	// Magnet is set when the info is parsed from magnet link, so Files are unknown
+	Magnet bool
+}
+
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidTorrent, fmt.Sprintf(format, args...))
+}
+
+// Parse parses and validates the .torrent file
+func Parse(data []byte) (*MetaInfo, error) {
+	dict, spans, err := bencode.DecodeDict(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTorrent, err)
+	}
+	info, ok := dict["info"].(map[string]any)
+	if !ok {
+		return nil, invalid("info dictionary not found")
+	}
+
+	sum := sha1.Sum(spans["info"])
+	mi := MetaInfo{InfoHash: hex.EncodeToString(sum[:])}
+	mi.Announce, _ = dict["announce"].(string)
+
+	if mi.Name, ok = info["name"].(string); !ok || mi.Name == "" {
+		return nil, invalid("name is missing")
+	}
+	if private, ok := info["private"].(int64); ok {
+		mi.Private = private == 1
+	}
+	if _, ok = info["pieces"].(string); !ok {
+		if _, v2 := info["file tree"]; !v2 {
+			return nil, invalid("pieces are missing")
+		}
+	}
+
+	if length, ok := info["length"].(int64); ok {
+		if length < 0 {
+			return nil, invalid("negative length")
+		}
+		mi.Files = []File{{Path: mi.Name, Size: length}}
+		mi.Size = length
+		return &mi, nil
+	}
+
+	files, ok := info["files"].([]any)
+	if !ok || len(files) == 0 {
+		return nil, invalid("files are missing")
+	}
+	for i, f := range files {
+		file, err := parseFile(f)
+		if err != nil {
+			return nil, invalid("file %d: %s", i, err)
+		}
+		mi.Files = append(mi.Files, file)
+		mi.Size += file.Size
+	}
+
+	return &mi, nil
+}
+
+func parseFile(f any) (File, error) {
+	dict, ok := f.(map[string]any)
+	if !ok {
+		return File{}, errors.New("dictionary expected")
+	}
+	length, ok := dict["length"].(int64)
+	if !ok || length < 0 {
+		return File{}, errors.New("invalid length")
+	}
+	parts, ok := dict["path"].([]any)
+	if !ok || len(parts) == 0 {
+		return File{}, errors.New("invalid path")
+	}
+	elems := make([]string, 0, len(parts))
+	for _, p := range parts {
+		s, ok := p.(string)
+		if !ok || s == "" || s == "." || s == ".." || strings.ContainsAny(s, "/\\") {
+			return File{}, fmt.Errorf("invalid path element '%v'", p)
+		}
+		elems = append(elems, s)
+	}
+	return File{Path: path.Join(elems...), Size: length}, nil
+}
+
+// InfoHash returns hex encoded infohash of the torrent file or magnet link
+func InfoHash(data []byte) (string, error) {
+	mi, err := Load(data)
+	if err != nil {
+		return "", err
+	}
+	return mi.InfoHash, nil
+}
@@ -0,0 +1,76 @@
+package metainfo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const pieces = "6:pieces20:01234567890123456789"
+
+func TestParse(t *testing.T) {
+	single := "d8:announce3:url4:infod6:lengthi1024e4:name8:film.mkv12:piece lengthi16384e" + pieces + "ee"
+	mi, err := Parse([]byte(single))
+	assert.NoError(t, err)
+	assert.Equal(t, "film.mkv", mi.Name)
+	assert.Equal(t, "url", mi.Announce)
+	assert.Equal(t, int64(1024), mi.Size)
+	assert.Equal(t, []File{{Path: "film.mkv", Size: 1024}}, mi.Files)
+	assert.Len(t, mi.InfoHash, 40)
+
+	multi := "d4:infod5:filesld6:lengthi100e4:pathl3:S017:E01.mkveed6:lengthi200e4:pathl3:S017:E02.mkveee" +
+		"4:name6:Series" + pieces + "7:privatei1eee"
+	mi, err = Parse([]byte(multi))
+	assert.NoError(t, err)
+	assert.Equal(t, "Series", mi.Name)
+	assert.True(t, mi.Private)
+	assert.Equal(t, int64(300), mi.Size)
+	assert.Equal(t, []File{{Path: "S01/E01.mkv", Size: 100}, {Path: "S01/E02.mkv", Size: 200}}, mi.Files)
+
+	hash, err := InfoHash([]byte("d7:comment4:test8:announce5:other4:infod6:lengthi1024e4:name8:film.mkv12:piece lengthi16384e" + pieces + "ee"))
+	assert.NoError(t, err)
+	mi, _ = Parse([]byte(single))
+	assert.Equal(t, mi.InfoHash, hash)
+
+	invalid := []string{
+		"",
+		"<html>Not found</html>",
+		"d8:announce3:urle",
+		"d4:infod6:lengthi1024e" + pieces + "ee",
+		"d4:infod6:lengthi1024e4:name8:film.mkvee",
+		"d4:infod5:filesle4:name6:Series" + pieces + "ee",
+		"d4:infod5:filesld6:lengthi100e4:pathl2:..eee4:name6:Series" + pieces + "ee",
+	}
+	for _, data := range invalid {
+		_, err = Parse([]byte(data))
+		assert.ErrorIs(t, err, ErrInvalidTorrent, data)
+	}
+}
+
+func TestParseMagnet(t *testing.T) {
+	const hash = "c12fe1c06bba254a9dc9f519b335aa7c1367a88a"
+	mi, err := Load([]byte("magnet:?xt=urn:btih:C12FE1C06BBA254A9DC9F519B335AA7C1367A88A&dn=Film+2020&tr=udp%3A%2F%2Ftracker.org%3A80&xl=1024"))
+	assert.NoError(t, err)
+	assert.True(t, mi.Magnet)
+	assert.Equal(t, hash, mi.InfoHash)
+	assert.Equal(t, "Film 2020", mi.Name)
+	assert.Equal(t, "udp://tracker.org:80", mi.Announce)
+	assert.Equal(t, int64(1024), mi.Size)
+	assert.Empty(t, mi.Files)
+
+	b32, err := InfoHash([]byte(" magnet:?xt=urn:btih:YEX6DQDLXISUVHOJ6UM3GNNKPQJWPKEK\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, hash, b32)
+
+	invalid := []string{
+		"magnet:?dn=Film",
+		"magnet:?xt=urn:btih:123",
+		"magnet:?xt=urn:sha1:c12fe1c06bba254a9dc9f519b335aa7c1367a88a",
+		"magnet:?xt=urn:btih:%zz",
+		"http://tracker.org/file.torrent",
+	}
+	for _, uri := range invalid {
+		_, err = ParseMagnet(uri)
+		assert.ErrorIs(t, err, ErrInvalidTorrent, uri)
+	}
+}
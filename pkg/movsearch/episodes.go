@@ -0,0 +1,62 @@
+package movsearch
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// EpisodesInfo describes which episodes of the season are presented in the release
+type EpisodesInfo struct {
+	From  uint
+	To    uint
+	Total uint
+}
+
+var episodesExpressions = []*regexp.Regexp{
+	// Серии: 1-8 из 10, [01-08 из 10]
+	regexp.MustCompile(`(\d{1,4})\s*-\s*(\d{1,4})\s*(?:из|of)\s*(\d{1,4})`),
+	// E01-E08, e01-08
+	regexp.MustCompile(`e(\d{1,4})\s*-\s*e?(\d{1,4})`),
+	// Серии: 1-8, episodes 1-8
+	regexp.MustCompile(`(?:серии|серия|эпизоды|episodes|episode|eps)\s*:?\s*(\d{1,4})\s*-\s*(\d{1,4})`),
+	// 8 из 10
+	regexp.MustCompile(`(\d{1,4})\s*(?:из|of)\s*(\d{1,4})`),
+}
+
+// ParseEpisodes extracts information about presented episodes from the release title
+func ParseEpisodes(title string) (EpisodesInfo, bool) {
+	title = strings.ToLower(title)
+	for i, exp := range episodesExpressions {
+		matches := exp.FindStringSubmatch(title)
+		if matches == nil {
+			continue
+		}
+		numbers := make([]uint, len(matches)-1)
+		for j := range numbers {
+			val, _ := strconv.ParseUint(matches[j+1], 10, 32)
+			numbers[j] = uint(val)
+		}
+
+		info := EpisodesInfo{}
+		switch i {
+		case 0:
+			info = EpisodesInfo{From: numbers[0], To: numbers[1], Total: numbers[2]}
+		case 1, 2:
+			info = EpisodesInfo{From: numbers[0], To: numbers[1]}
+		case 3:
+			info = EpisodesInfo{From: 1, To: numbers[0], Total: numbers[1]}
+		}
+		if info.From == 0 || info.From > info.To || (info.Total != 0 && info.To > info.Total) {
+			continue
+		}
+		return info, true
+	}
+
+	return EpisodesInfo{}, false
+}
+
+// IsComplete checks whether the release contains all episodes of the season
+func (i EpisodesInfo) IsComplete() bool {
+	return i.Total != 0 && i.From == 1 && i.To == i.Total
+}
@@ -0,0 +1,49 @@
+package movsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEpisodes(t *testing.T) {
+	type testCase struct {
+		input  string
+		output EpisodesInfo
+		ok     bool
+	}
+
+	testCases := []testCase{
+		{
+			input:  "Одни из нас / The Last of Us / Сезон: 2 / Серии: 1-3 из 7 (Крэйг Мэйзин) [2025, WEB-DL 1080p]",
+			output: EpisodesInfo{From: 1, To: 3, Total: 7},
+			ok:     true,
+		},
+		{
+			input:  "Severance.S02E01-E06.1080p.WEB-DL",
+			output: EpisodesInfo{From: 1, To: 6},
+			ok:     true,
+		},
+		{
+			input:  "Андор / Andor [S01] Серии 1-12 [2022, WEB-DL 2160p]",
+			output: EpisodesInfo{From: 1, To: 12},
+			ok:     true,
+		},
+		{
+			input:  "Пацаны / The Boys [04x01-08 из 08] (2024) WEB-DL 1080p",
+			output: EpisodesInfo{From: 1, To: 8, Total: 8},
+			ok:     true,
+		},
+		{
+			input:  "Во все тяжкие / Breaking Bad [Сезон 5, 2012, BDRip 1080p]",
+			output: EpisodesInfo{},
+			ok:     false,
+		},
+	}
+
+	for i, tc := range testCases {
+		actual, ok := ParseEpisodes(tc.input)
+		assert.Equal(t, tc.ok, ok, "Test %d failed", i)
+		assert.Equal(t, tc.output, actual, "Test %d failed", i)
+	}
+}
@@ -2,51 +2,123 @@ package movsearch
 
 import (
 	"context"
+	"sync"
 
 	"github.com/RacoonMediaServer/rms-library/v3/pkg/selector"
 	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
 )
 
+// DefaultParallelism is a default count of seasons searched simultaneously
+const DefaultParallelism uint = 4
+
 type ExcludeStrategy struct {
 	Engine   SearchEngine
 	Selector selector.MediaSelector
 	Exclude  Seasons
+
+	// Parallel limits count of seasons searched simultaneously. Zero means DefaultParallelism
+	Parallel uint
+}
+
+type seasonSearchResult struct {
+	torrents []Result
+	err      error
 }
 
 func (s ExcludeStrategy) Search(ctx context.Context, id string, info *rms_library.MovieInfo, selopts selector.Options) ([]Result, error) {
-	result := []Result{}
+	// без информации о количестве сезонов перебирать нечего
+	if info.Seasons == nil || *info.Seasons == 0 {
+		return nil, ErrAnyTorrentsNotFound
+	}
+	if s.Exclude == nil {
+		s.Exclude = Seasons{}
+	}
+	parallel := s.Parallel
+	if parallel == 0 {
+		parallel = DefaultParallelism
+	}
 
-	seasonSearcher := SeasonStrategy{Engine: s.Engine, Selector: s.Selector, SeasonNo: 1}
+	total := uint(*info.Seasons)
+	results := make([]*seasonSearchResult, total+1)
 
-	seasonsKnown := info.Seasons != nil
-	shouldContinueIterate := func(seasonNo uint) bool {
-		if seasonsKnown {
-			return seasonNo <= uint(*info.Seasons)
-		}
-		return false
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, parallel)
+
+	// covered пополняется по мере нахождения раздач, чтобы не искать сезоны, которые в них уже есть
+	covered := Seasons{}
+	covered.Union(s.Exclude)
+	isExcluded := func(season uint) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		_, found := covered[season]
+		return found
 	}
 
-	for season := uint(1); shouldContinueIterate(season); season++ {
-		_, found := s.Exclude[season]
-		if found {
+Loop:
+	for season := uint(1); season <= total; season++ {
+		if isExcluded(season) {
+			continue
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break Loop
+		}
+
+		// пока ждали слот, сезон мог оказаться в уже найденной раздаче
+		if isExcluded(season) {
+			<-sem
 			continue
 		}
-		seasonSearcher.SeasonNo = season
-		torrents, err := seasonSearcher.Search(ctx, id, info, selopts)
-		if err == nil {
-			result = append(result, torrents...)
-			detectedSeasons := GetMultipleResultsSeasons(torrents)
-			for seasonNo := range detectedSeasons {
-				s.Exclude[seasonNo] = struct{}{}
+
+		wg.Add(1)
+		go func(season uint) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			seasonSearcher := SeasonStrategy{Engine: s.Engine, Selector: s.Selector, SeasonNo: season}
+			torrents, err := seasonSearcher.Search(ctx, id, info, selopts)
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[season] = &seasonSearchResult{torrents: torrents, err: err}
+			if err == nil {
+				covered.Union(GetMultipleResultsSeasons(torrents))
 			}
-		} else if !seasonsKnown {
-			break
+		}(season)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// сливаем в порядке сезонов, отбрасывая раздачи, сезоны которых уже покрыты предыдущими
+	merged := Seasons{}
+	merged.Union(s.Exclude)
+	result := []Result{}
+	for season := uint(1); season <= total; season++ {
+		r := results[season]
+		if r == nil || r.err != nil {
+			continue
+		}
+		detectedSeasons := GetMultipleResultsSeasons(r.torrents)
+		if len(detectedSeasons) == 0 {
+			detectedSeasons = Seasons{season: struct{}{}}
+		}
+		if merged.Contains(detectedSeasons) {
+			continue
 		}
+		merged.Union(detectedSeasons)
+		result = append(result, r.torrents...)
 	}
+	s.Exclude.Union(merged)
 
 	if len(result) == 0 {
 		return nil, ErrAnyTorrentsNotFound
 	}
 
-	return removeDuplicatedResults(result), nil
+	return ResolveOverlaps(result), nil
 }
@@ -0,0 +1,99 @@
+package movsearch
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/selector"
+	"github.com/RacoonMediaServer/rms-media-discovery/pkg/client/models"
+	"github.com/RacoonMediaServer/rms-media-discovery/pkg/media"
+	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeEngine struct {
+	seasons map[uint][]int64
+	delay   time.Duration
+
+	mu       sync.Mutex
+	searched []uint
+	active   int32
+	maxAct   int32
+}
+
+func (e *fakeEngine) SearchTorrents(ctx context.Context, id string, info *rms_library.MovieInfo, season *uint) ([]*models.SearchTorrentsResult, error) {
+	act := atomic.AddInt32(&e.active, 1)
+	defer atomic.AddInt32(&e.active, -1)
+	for {
+		cur := atomic.LoadInt32(&e.maxAct)
+		if act <= cur || atomic.CompareAndSwapInt32(&e.maxAct, cur, act) {
+			break
+		}
+	}
+
+	e.mu.Lock()
+	e.searched = append(e.searched, *season)
+	e.mu.Unlock()
+
+	select {
+	case <-time.After(e.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	seasons, ok := e.seasons[*season]
+	if !ok {
+		return nil, ErrAnyTorrentsNotFound
+	}
+	link := fmt.Sprintf("torrent-%v", seasons)
+	size := int64(2048 * len(seasons))
+	return []*models.SearchTorrentsResult{{Link: &link, Title: &link, Size: &size, Seasons: seasons}}, nil
+}
+
+func (e *fakeEngine) GetTorrentFile(ctx context.Context, link string) ([]byte, error) {
+	return []byte(link), nil
+}
+
+func (e *fakeEngine) SetNext(next SearchEngine) {}
+
+func TestExcludeStrategy_Search(t *testing.T) {
+	engine := &fakeEngine{
+		delay: 20 * time.Millisecond,
+		seasons: map[uint][]int64{
+			1: {1},
+			2: {2, 3},
+			3: {3},
+			4: {4},
+			6: {6},
+			7: {6, 7},
+		},
+	}
+	seasons := uint32(8)
+	info := &rms_library.MovieInfo{Title: "Series", Type: rms_library.MovieType_TvSeries, Seasons: &seasons}
+	sel := selector.New(selector.Settings{MaxSeasonSizeMB: 50 * 1024, QualityPrior: []string{"1080p"}})
+	opts := selector.Options{Criteria: selector.CriteriaQuality, MediaType: media.Movies}
+
+	exclude := Seasons{4: struct{}{}}
+	strategy := ExcludeStrategy{Engine: engine, Selector: sel, Exclude: exclude, Parallel: 3}
+	result, err := strategy.Search(context.Background(), "id", info, opts)
+	assert.NoError(t, err)
+
+	torrents := []string{}
+	for _, r := range result {
+		torrents = append(torrents, string(r.Torrent))
+	}
+	assert.Equal(t, []string{"torrent-[1]", "torrent-[2 3]", "torrent-[6 7]"}, torrents)
+	assert.NotContains(t, engine.searched, uint(4))
+	assert.LessOrEqual(t, engine.maxAct, int32(3))
+	assert.Greater(t, engine.maxAct, int32(1))
+	assert.True(t, exclude.Contains(Seasons{1: {}, 2: {}, 3: {}, 4: {}, 6: {}, 7: {}}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = ExcludeStrategy{Engine: engine, Selector: sel}.Search(ctx, "id", info, opts)
+	assert.ErrorIs(t, err, context.Canceled)
+}
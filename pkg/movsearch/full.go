@@ -10,6 +10,9 @@ import (
 type FullStrategy struct {
 	Engine   SearchEngine
 	Selector selector.MediaSelector
+
+	// Parallel limits count of seasons searched simultaneously. Zero means DefaultParallelism
+	Parallel uint
 }
 
 func (s FullStrategy) Search(ctx context.Context, id string, info *rms_library.MovieInfo, selopts selector.Options) ([]Result, error) {
@@ -18,14 +21,14 @@ func (s FullStrategy) Search(ctx context.Context, id string, info *rms_library.M
 
 	seasonSearcher := SeasonStrategy{Engine: s.Engine, Selector: s.Selector, SeasonNo: 1}
 	simpleSearcher := SimpleStrategy{Engine: s.Engine, Selector: s.Selector}
-	excludeSearcher := ExcludeStrategy{Engine: s.Engine, Selector: s.Selector, Exclude: seasons}
+	excludeSearcher := ExcludeStrategy{Engine: s.Engine, Selector: s.Selector, Exclude: seasons, Parallel: s.Parallel}
 
 	skipAtOnce := false
-	// если нужно быстро - выкачиваем сразу первый сезон
-	if selopts.Criteria == selector.CriteriaFastest {
+	// если нужно быстро или компактно - выкачиваем сразу первый сезон
+	if selopts.Criteria == selector.CriteriaFastest || selopts.Criteria == selector.CriteriaSmallest {
 		season1, err := seasonSearcher.Search(ctx, id, info, selopts)
 		if err == nil {
-			seasons = season1[0].Seasons
+			seasons.Union(season1[0].Seasons)
 			result = append(result, season1...)
 			skipAtOnce = true
 		}
@@ -37,7 +40,7 @@ func (s FullStrategy) Search(ctx context.Context, id string, info *rms_library.M
 		compactOpts.Criteria = selector.CriteriaCompact
 		found, err := simpleSearcher.Search(ctx, id, info, compactOpts)
 		if err == nil {
-			seasons = GetMultipleResultsSeasons(found)
+			seasons.Union(GetMultipleResultsSeasons(found))
 			result = append(result, found...)
 		}
 	}
@@ -46,13 +49,13 @@ func (s FullStrategy) Search(ctx context.Context, id string, info *rms_library.M
 	torrents, err := excludeSearcher.Search(ctx, id, info, selopts)
 	if err == nil {
 		result = append(result, torrents...)
-		detectedSeasons := GetMultipleResultsSeasons(torrents)
-		seasons.Union(detectedSeasons)
+	} else if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
 	}
 
 	if len(result) == 0 {
 		return nil, ErrAnyTorrentsNotFound
 	}
 
-	return removeDuplicatedResults(result), nil
+	return ResolveOverlaps(result), nil
 }
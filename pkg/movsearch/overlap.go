@@ -0,0 +1,112 @@
+package movsearch
+
+import (
+	"hash/crc32"
+	"strconv"
+
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/metainfo"
+)
+
+func getResultKey(r Result) string {
+	if hash, err := metainfo.InfoHash(r.Torrent); err == nil {
+		return hash
+	}
+	return "crc:" + strconv.FormatUint(uint64(crc32.ChecksumIEEE(r.Torrent)), 16)
+}
+
+// removeDuplicatedResults removes the same torrents keeping order of the first occurrences
+func removeDuplicatedResults(src []Result) []Result {
+	if len(src) < 2 {
+		return src
+	}
+
+	keys := map[string]struct{}{}
+	result := make([]Result, 0, len(src))
+	for _, r := range src {
+		key := getResultKey(r)
+		if _, found := keys[key]; !found {
+			keys[key] = struct{}{}
+			result = append(result, r)
+		}
+	}
+
+	return result
+}
+
+// ResolveOverlaps chooses the minimal set of results, which covers all found seasons. Larger packs are preferred,
+// among packs of equal coverage the larger torrent wins. Ranks aren't compared, because they come from different searches.
+// Results without seasons are kept as is. Order of results is preserved
+func ResolveOverlaps(src []Result) []Result {
+	src = removeDuplicatedResults(src)
+
+	uncovered := Seasons{}
+	for _, r := range src {
+		uncovered.Union(r.Seasons)
+	}
+
+	// жадное покрытие: выбираем раздачу с наибольшим числом непокрытых сезонов, при равенстве - большую по размеру
+	chosen := make([]bool, len(src))
+	for len(uncovered) != 0 {
+		best := -1
+		bestCount := 0
+		var bestSize int64
+		for i, r := range src {
+			if chosen[i] {
+				continue
+			}
+			count := countCommonSeasons(uncovered, r.Seasons)
+			size := getResultSize(r)
+			if count > bestCount || (count == bestCount && count != 0 && size > bestSize) {
+				best, bestCount, bestSize = i, count, size
+			}
+		}
+		if best < 0 {
+			break
+		}
+		chosen[best] = true
+		for no := range src[best].Seasons {
+			delete(uncovered, no)
+		}
+	}
+
+	// убираем раздачи, сезоны которых полностью покрыты остальными выбранными
+	for i := len(src) - 1; i >= 0; i-- {
+		if !chosen[i] {
+			continue
+		}
+		others := Seasons{}
+		for j := range src {
+			if j != i && chosen[j] {
+				others.Union(src[j].Seasons)
+			}
+		}
+		if others.Contains(src[i].Seasons) {
+			chosen[i] = false
+		}
+	}
+
+	result := make([]Result, 0, len(src))
+	for i, r := range src {
+		if len(r.Seasons) == 0 || chosen[i] {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+func getResultSize(r Result) int64 {
+	if r.Info == nil || r.Info.Size == nil {
+		return 0
+	}
+	return *r.Info.Size
+}
+
+func countCommonSeasons(a, b Seasons) int {
+	count := 0
+	for no := range b {
+		if _, found := a[no]; found {
+			count++
+		}
+	}
+	return count
+}
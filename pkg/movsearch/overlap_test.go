@@ -0,0 +1,67 @@
+package movsearch
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/RacoonMediaServer/rms-media-discovery/pkg/client/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPieces = "6:pieces20:01234567890123456789"
+
+func makeTorrent(name string, private bool) []byte {
+	info := fmt.Sprintf("d6:lengthi1024e4:name%d:%s%se", len(name), name, testPieces)
+	if private {
+		info = fmt.Sprintf("d6:lengthi1024e4:name%d:%s%s7:privatei1ee", len(name), name, testPieces)
+	}
+	return []byte("d8:announce3:url4:info" + info + "e")
+}
+
+func makeResult(name string, rank float32, seasons ...uint) Result {
+	r := Result{Torrent: makeTorrent(name, false), Seasons: Seasons{}, Rank: rank}
+	for _, no := range seasons {
+		r.Seasons[no] = struct{}{}
+	}
+	return r
+}
+
+func getNames(results []Result) []string {
+	names := make([]string, 0, len(results))
+	for _, r := range results {
+		names = append(names, string(r.Torrent))
+	}
+	return names
+}
+
+func TestResolveOverlaps(t *testing.T) {
+	largeSize := int64(40000)
+	pack := makeResult("S01-S03", 1, 1, 2, 3)
+	s2 := makeResult("S02", 2, 2)
+	s4 := makeResult("S04", 1, 4)
+	s34 := makeResult("S03-S04", 1, 3, 4)
+	film := makeResult("film", 1)
+	s4large := makeResult("S04.2160p", 0, 4)
+	s4large.Info = &models.SearchTorrentsResult{Size: &largeSize}
+	duplicate := Result{Torrent: []byte("d7:comment3:dup8:announce3:url4:info" + string(makeTorrent("S04", false)[22:]))}
+	duplicate.Seasons = Seasons{4: struct{}{}}
+
+	testCases := []struct {
+		input  []Result
+		output []Result
+	}{
+		{input: nil, output: []Result{}},
+		{input: []Result{film}, output: []Result{film}},
+		{input: []Result{pack, s2}, output: []Result{pack}},
+		{input: []Result{s2, pack, s4}, output: []Result{pack, s4}},
+		{input: []Result{pack, s34, s4}, output: []Result{pack, s34}},
+		{input: []Result{s4, duplicate}, output: []Result{s4}},
+		{input: []Result{film, pack, pack}, output: []Result{film, pack}},
+		{input: []Result{s4, s4large}, output: []Result{s4large}},
+		{input: []Result{s2, s4large, pack}, output: []Result{s4large, pack}},
+	}
+
+	for i, tc := range testCases {
+		assert.Equal(t, getNames(tc.output), getNames(ResolveOverlaps(tc.input)), "test case %d", i)
+	}
+}
@@ -1,8 +1,16 @@
 package movsearch
 
+import "github.com/RacoonMediaServer/rms-media-discovery/pkg/client/models"
+
 type Result struct {
 	Torrent []byte
 	Seasons Seasons
+
+	// Info is a search result which the torrent was selected from
+	Info *models.SearchTorrentsResult
+
+	// Rank is a rank of the torrent among candidates of the search
+	Rank float32
 }
 
 func GetMultipleResultsSeasons(results []Result) Seasons {
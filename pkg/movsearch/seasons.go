@@ -7,3 +7,13 @@ func (s Seasons) Union(other Seasons) {
 		s[seasonNo] = struct{}{}
 	}
 }
+
+// Contains checks all seasons of other are presented
+func (s Seasons) Contains(other Seasons) bool {
+	for seasonNo := range other {
+		if _, found := s[seasonNo]; !found {
+			return false
+		}
+	}
+	return true
+}
@@ -2,6 +2,7 @@ package movsearch
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/RacoonMediaServer/rms-library/v3/pkg/selector"
 	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
@@ -18,11 +19,14 @@ func (s SimpleStrategy) Search(ctx context.Context, id string, info *rms_library
 		return nil, err
 	}
 
-	result := s.Selector.Select(torrents, selopts)
+	result, rank, err := s.Selector.SelectRanked(torrents, selopts)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAnyTorrentsNotFound, err)
+	}
 	torrentFile, err := s.Engine.GetTorrentFile(ctx, *result.Link)
 	if err != nil {
 		return nil, err
 	}
 
-	return []Result{{Torrent: torrentFile}}, nil
+	return []Result{{Torrent: torrentFile, Seasons: extractSeasonsFromResult(result), Info: result, Rank: rank}}, nil
 }
@@ -0,0 +1,54 @@
+package movsearch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/selector"
+	"github.com/RacoonMediaServer/rms-media-discovery/pkg/client/models"
+	"github.com/RacoonMediaServer/rms-media-discovery/pkg/media"
+	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
+	"github.com/stretchr/testify/assert"
+)
+
+type listEngine struct {
+	list []*models.SearchTorrentsResult
+}
+
+func (e listEngine) SearchTorrents(ctx context.Context, id string, info *rms_library.MovieInfo, season *uint) ([]*models.SearchTorrentsResult, error) {
+	return e.list, nil
+}
+
+func (e listEngine) GetTorrentFile(ctx context.Context, link string) ([]byte, error) {
+	return []byte(link), nil
+}
+
+func (e listEngine) SetNext(next SearchEngine) {}
+
+func TestSimpleStrategy_NoLink(t *testing.T) {
+	newTorrent := func(link *string, seeders int64) *models.SearchTorrentsResult {
+		title := "Dune (2021) BDRip 1080p"
+		size := int64(8000)
+		return &models.SearchTorrentsResult{Link: link, Title: &title, Size: &size, Seeders: &seeders, Quality: "1080p"}
+	}
+	link := "torrent"
+	empty := ""
+	info := &rms_library.MovieInfo{Title: "Dune", Type: rms_library.MovieType_Film}
+	sel := selector.New(selector.Settings{MaxSeasonSizeMB: 50 * 1024, QualityPrior: []string{"1080p"}})
+	opts := selector.Options{Criteria: selector.CriteriaFastest, MediaType: media.Movies}
+
+	strategy := SimpleStrategy{
+		Engine:   listEngine{list: []*models.SearchTorrentsResult{newTorrent(nil, 1000), newTorrent(&empty, 500), newTorrent(&link, 10)}},
+		Selector: sel,
+	}
+	result, err := strategy.Search(context.Background(), "id", info, opts)
+	assert.NoError(t, err)
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, []byte(link), result[0].Torrent)
+	}
+
+	strategy.Engine = listEngine{list: []*models.SearchTorrentsResult{newTorrent(nil, 1000)}}
+	_, err = strategy.Search(context.Background(), "id", info, opts)
+	assert.ErrorIs(t, err, ErrAnyTorrentsNotFound)
+	assert.ErrorIs(t, err, selector.ErrNoCandidates)
+}
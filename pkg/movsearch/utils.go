@@ -1,8 +1,6 @@
 package movsearch
 
 import (
-	"hash/crc32"
-
 	"github.com/RacoonMediaServer/rms-media-discovery/pkg/client/models"
 )
 
@@ -13,21 +11,3 @@ func extractSeasonsFromResult(result *models.SearchTorrentsResult) Seasons {
 	}
 	return seasons
 }
-
-func removeDuplicatedResults(src []Result) []Result {
-	if len(src) < 2 {
-		return src
-	}
-
-	crc := map[uint32]Result{}
-	for _, r := range src {
-		crc[crc32.ChecksumIEEE(r.Torrent)] = r
-	}
-
-	result := make([]Result, 0, len(crc))
-	for _, r := range crc {
-		result = append(result, r)
-	}
-
-	return result
-}
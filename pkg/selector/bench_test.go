@@ -0,0 +1,75 @@
+package selector
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/RacoonMediaServer/rms-media-discovery/pkg/client/models"
+	"github.com/RacoonMediaServer/rms-media-discovery/pkg/media"
+)
+
+var benchQualities = []string{"1080p", "720p", "480p", ""}
+var benchVoices = []string{"Дублированный", "LostFilm", "NewStudio", "Original", ""}
+
+func makeBenchList(n int) []*models.SearchTorrentsResult {
+	r := rand.New(rand.NewSource(1))
+	list := make([]*models.SearchTorrentsResult, n)
+	for i := range list {
+		seasons := make([]int64, r.Intn(4)+1)
+		for j := range seasons {
+			seasons[j] = int64(j + 1)
+		}
+		list[i] = &models.SearchTorrentsResult{
+			Title:   makeValuePtr(fmt.Sprintf("Release %d WEB-DL HEVC", i)),
+			Link:    makeValuePtr(fmt.Sprintf("https://rutracker.org/forum/viewtopic.php?t=%d", 1000000+i)),
+			Size:    makeValuePtr(r.Int63n(60000) + 500),
+			Seeders: makeValuePtr(r.Int63n(2000)),
+			Quality: benchQualities[r.Intn(len(benchQualities))],
+			Voice:   benchVoices[r.Intn(len(benchVoices))],
+			Seasons: seasons,
+		}
+	}
+	return list
+}
+
+func makeBenchSelector() MediaSelector {
+	settings := Settings{
+		MinSeasonSizeMB:     1024,
+		MaxSeasonSizeMB:     50 * 1024,
+		MinSeedersThreshold: 50,
+		QualityPrior:        []string{"1080p", "720p", "480p"},
+	}
+	settings.VoiceList.Append("lostfilm")
+	settings.VoiceList.Append("newstudio")
+	return New(settings)
+}
+
+func BenchmarkMediaSelector_Select(b *testing.B) {
+	sel := makeBenchSelector()
+	for _, n := range []int{100, 1000, 10000} {
+		list := makeBenchList(n)
+		for _, criteria := range []Criteria{CriteriaQuality, CriteriaFastest, CriteriaCompact, CriteriaBalanced, CriteriaSmallest} {
+			opts := Options{Criteria: criteria, MediaType: media.Movies}
+			b.Run(fmt.Sprintf("%s/%d", criteria, n), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					sel.Select(list, opts)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkMediaSelector_Sort(b *testing.B) {
+	sel := makeBenchSelector()
+	opts := Options{Criteria: CriteriaQuality, MediaType: media.Movies}
+	for _, n := range []int{100, 1000, 10000} {
+		list := makeBenchList(n)
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				tmp := append([]*models.SearchTorrentsResult{}, list...)
+				sel.Sort(tmp, opts)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+// Package corpus implements regression corpus of torrents selection: real search results with expected picks
+package corpus
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/selector"
+	"github.com/RacoonMediaServer/rms-media-discovery/pkg/client/models"
+	"github.com/RacoonMediaServer/rms-media-discovery/pkg/media"
+)
+
+// Case is a list of search results with expected pick (torrent link) per criteria
+type Case struct {
+	Name        string
+	Settings    selector.Settings
+	Query       string
+	MediaType   media.ContentType
+	Discography bool
+	List        []*models.SearchTorrentsResult
+	Expected    map[selector.Criteria]string
+}
+
+// Candidate is a torrent picked by the selector
+type Candidate struct {
+	Index       int
+	Link        string
+	Title       string
+	Explanation selector.Explanation
+}
+
+// Outcome is a result of replaying the case for the criteria
+type Outcome struct {
+	Case     string
+	Criteria selector.Criteria
+	Expected *Candidate
+	Actual   *Candidate
+}
+
+// Passed checks the selector picked the expected torrent
+func (o Outcome) Passed() bool {
+	if o.Expected == nil || o.Actual == nil {
+		return o.Expected == o.Actual
+	}
+	return o.Expected.Link == o.Actual.Link
+}
+
+// LoadFile reads the case from JSON file
+func LoadFile(path string) (*Case, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := Case{}
+	if err = json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parse %s failed: %w", path, err)
+	}
+	if c.Name == "" {
+		c.Name = filepath.Base(path)
+	}
+	return &c, nil
+}
+
+// Load reads all cases of the directory
+func Load(dir string) ([]*Case, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	result := make([]*Case, 0, len(files))
+	for _, f := range files {
+		c, err := LoadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+func (c *Case) options(criteria selector.Criteria) selector.Options {
+	return selector.Options{
+		Criteria:    criteria,
+		MediaType:   c.MediaType,
+		Query:       c.Query,
+		Discography: c.Discography,
+	}
+}
+
+func (c *Case) find(link string, explanations []selector.Explanation) *Candidate {
+	for i, t := range c.List {
+		if t.Link != nil && *t.Link == link {
+			candidate := &Candidate{Index: i, Link: link, Explanation: explanations[i]}
+			if t.Title != nil {
+				candidate.Title = *t.Title
+			}
+			return candidate
+		}
+	}
+	return nil
+}
+
+// Replay runs the selector over the case for all expected criteria
+func (c *Case) Replay() []Outcome {
+	criteria := make([]selector.Criteria, 0, len(c.Expected))
+	for cr := range c.Expected {
+		criteria = append(criteria, cr)
+	}
+	sort.Slice(criteria, func(i, j int) bool { return criteria[i] < criteria[j] })
+
+	sel := selector.New(c.Settings)
+	result := make([]Outcome, 0, len(criteria))
+	for _, cr := range criteria {
+		opts := c.options(cr)
+		explanations, _ := sel.Explain(c.List, opts)
+		outcome := Outcome{
+			Case:     c.Name,
+			Criteria: cr,
+			Expected: c.find(c.Expected[cr], explanations),
+		}
+		if selected, err := sel.Select(c.List, opts); err == nil && selected.Link != nil {
+			outcome.Actual = c.find(*selected.Link, explanations)
+		}
+		result = append(result, outcome)
+	}
+	return result
+}
@@ -0,0 +1,28 @@
+package selector_test
+
+import (
+	"testing"
+
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/selector/corpus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCorpus(t *testing.T) {
+	cases, err := corpus.Load("testdata/corpus")
+	require.NoError(t, err)
+	require.NotEmpty(t, cases)
+
+	for _, c := range cases {
+		for _, o := range c.Replay() {
+			if !assert.True(t, o.Passed(), "%s [%s]", o.Case, o.Criteria) {
+				if o.Expected != nil {
+					t.Logf("expected: %s (%.4f)", o.Expected.Title, o.Expected.Explanation.Total)
+				}
+				if o.Actual != nil {
+					t.Logf("actual: %s (%.4f)", o.Actual.Title, o.Actual.Explanation.Total)
+				}
+			}
+		}
+	}
+}
@@ -1,9 +1,16 @@
 package selector
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+
 	"github.com/RacoonMediaServer/rms-media-discovery/pkg/client/models"
 )
 
+// ErrUnknownCriteria means there is no ranking pipeline for the criteria
+var ErrUnknownCriteria = errors.New("unknown criteria")
+
 type Criteria int
 
 const (
@@ -15,19 +22,56 @@ const (
 
 	// CriteriaCompact отдавать приоритет раздачам, в которых больше всего сезонов
 	CriteriaCompact
+
+	// CriteriaBalanced отдавать приоритет раздачам с лучшим соотношением качества и размера
+	CriteriaBalanced
+
+	// CriteriaSmallest выбирать самую маленькую раздачу, удовлетворяющую минимальному качеству и озвучке
+	CriteriaSmallest
 )
 
-type rankFunc func(list []*models.SearchTorrentsResult) []float32
+var criteriaNames = map[Criteria]string{
+	CriteriaQuality:  "quality",
+	CriteriaFastest:  "fastest",
+	CriteriaCompact:  "compact",
+	CriteriaBalanced: "balanced",
+	CriteriaSmallest: "smallest",
+}
+
+func (c Criteria) String() string {
+	if name, ok := criteriaNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("Criteria(%d)", int(c))
+}
 
-func makeRankFunc(funcs ...rankFunc) rankFunc {
-	return func(list []*models.SearchTorrentsResult) []float32 {
-		ranks := make([]float32, len(list))
-		for _, f := range funcs {
-			fRanks := f(list)
-			for i := range ranks {
-				ranks[i] += fRanks[i]
-			}
+// MarshalText implements encoding.TextMarshaler
+func (c Criteria) MarshalText() ([]byte, error) {
+	if _, ok := criteriaNames[c]; !ok {
+		return nil, fmt.Errorf("unknown criteria: %d", int(c))
+	}
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (c *Criteria) UnmarshalText(text []byte) error {
+	criteria, err := ParseCriteria(string(text))
+	if err != nil {
+		return err
+	}
+	*c = criteria
+	return nil
+}
+
+// ParseCriteria returns criteria by its name
+func ParseCriteria(name string) (Criteria, error) {
+	for c, n := range criteriaNames {
+		if strings.EqualFold(n, name) {
+			return c, nil
 		}
-		return ranks
 	}
+	return 0, fmt.Errorf("unknown criteria: %s", name)
 }
+
+// RankFunc returns rank of each item of the list, usually in range [0; 1]
+type RankFunc func(list []*models.SearchTorrentsResult) []float32
@@ -0,0 +1,19 @@
+package selector
+
+// Score is a rank of the candidate by the single criterion
+type Score struct {
+	// Name of the rank function
+	Name string
+
+	// Weight of the rank function in the total rank
+	Weight float32
+
+	// Rank is a raw (not weighted) value of the rank function
+	Rank float32
+}
+
+// Explanation is a breakdown of the candidate total rank
+type Explanation struct {
+	Total  float32
+	Scores []Score
+}
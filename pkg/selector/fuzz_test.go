@@ -0,0 +1,143 @@
+package selector
+
+import (
+	"math"
+	"testing"
+
+	"github.com/RacoonMediaServer/rms-media-discovery/pkg/client/models"
+	"github.com/RacoonMediaServer/rms-media-discovery/pkg/media"
+	"github.com/stretchr/testify/assert"
+)
+
+var fuzzStrings = []string{"", "1080p", "720p", "Дубляж", "LostFilm", "Original", "HEVC HDR", "https://rutracker.org/forum/viewtopic.php?t=1", "%%%"}
+
+// makeFuzzList builds list of torrents from arbitrary bytes, including nil items and nil fields
+func makeFuzzList(data []byte) []*models.SearchTorrentsResult {
+	next := func() int {
+		if len(data) == 0 {
+			return 0
+		}
+		b := data[0]
+		data = data[1:]
+		return int(b)
+	}
+	str := func() *string {
+		n := next()
+		if n%7 == 0 {
+			return nil
+		}
+		return makeValuePtr(fuzzStrings[n%len(fuzzStrings)])
+	}
+	num := func() *int64 {
+		n := next()
+		if n%5 == 0 {
+			return nil
+		}
+		return makeValuePtr(int64(n-64) * int64(next()+1) * 100)
+	}
+
+	var list []*models.SearchTorrentsResult
+	for count := next() % 16; count > 0 && len(data) != 0; count-- {
+		if next()%9 == 0 {
+			list = append(list, nil)
+			continue
+		}
+		t := &models.SearchTorrentsResult{
+			Title:   str(),
+			Link:    str(),
+			Size:    num(),
+			Seeders: num(),
+			Quality: fuzzStrings[next()%len(fuzzStrings)],
+			Voice:   fuzzStrings[next()%len(fuzzStrings)],
+		}
+		for s := next() % 4; s > 0; s-- {
+			t.Seasons = append(t.Seasons, int64(next()%10))
+		}
+		list = append(list, t)
+	}
+	return list
+}
+
+func FuzzMediaSelector(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{3, 1, 1, 2, 3, 4, 5, 6, 7, 8, 2, 1, 1, 2, 0, 0, 0, 0, 0, 0, 0, 1})
+	f.Add([]byte{5, 9, 1, 1, 0, 0, 7, 7, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15})
+
+	settings := Settings{
+		MinSeasonSizeMB:     1024,
+		MaxSeasonSizeMB:     50 * 1024,
+		MinSeedersThreshold: 50,
+		QualityPrior:        []string{"1080p", "720p", "480p"},
+		MinQuality:          "720p",
+		Languages:           []string{"original", "rus"},
+		SubtitleLanguages:   []string{"eng"},
+	}
+	settings.VoiceList.Append("lostfilm")
+	sel := New(settings)
+	criterias := []Criteria{CriteriaQuality, CriteriaFastest, CriteriaCompact, CriteriaBalanced, CriteriaSmallest}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		list := makeFuzzList(data)
+		for _, mediaType := range []media.ContentType{media.Movies, media.Music, media.Other} {
+			for _, criteria := range criterias {
+				opts := Options{Criteria: criteria, MediaType: mediaType, Query: "query"}
+
+				ranks, err := sel.Rank(list, opts)
+				assert.NoError(t, err)
+				for _, r := range ranks {
+					assert.False(t, math.IsNaN(float64(r)) || math.IsInf(float64(r), 0), "rank must be finite")
+				}
+
+				selected, err := sel.Select(list, opts)
+				if err != nil {
+					assert.ErrorIs(t, err, ErrNoCandidates)
+				} else {
+					assert.Contains(t, list, selected)
+				}
+
+				sorted := append([]*models.SearchTorrentsResult{}, list...)
+				assert.NoError(t, sel.Sort(sorted, opts))
+				assert.ElementsMatch(t, list, sorted)
+			}
+		}
+	})
+}
+
+func TestMediaSelector_Degenerate(t *testing.T) {
+	sel := New(Settings{})
+	opts := Options{Criteria: CriteriaQuality, MediaType: media.Movies}
+
+	_, err := sel.Select(nil, opts)
+	assert.ErrorIs(t, err, ErrNoCandidates)
+
+	_, err = sel.Select([]*models.SearchTorrentsResult{nil}, opts)
+	assert.ErrorIs(t, err, ErrNoCandidates)
+
+	zero := &models.SearchTorrentsResult{Size: makeValuePtr[int64](0), Seeders: makeValuePtr[int64](0)}
+	list := []*models.SearchTorrentsResult{nil, zero, {}}
+	for _, criteria := range []Criteria{CriteriaQuality, CriteriaFastest, CriteriaCompact, CriteriaBalanced, CriteriaSmallest} {
+		opts.Criteria = criteria
+		ranks, err := sel.Rank(list, opts)
+		assert.NoError(t, err)
+		for _, r := range ranks {
+			assert.False(t, math.IsNaN(float64(r)), "rank must not be NaN")
+		}
+	}
+
+	assert.NoError(t, sel.Sort(list, opts))
+	assert.Nil(t, list[2])
+}
+
+func TestMediaSelector_UnknownCriteria(t *testing.T) {
+	sel := New(Settings{})
+	opts := Options{Criteria: Criteria(100), MediaType: media.Movies}
+	list := []*models.SearchTorrentsResult{{Title: makeValuePtr("Dune (2021)"), Link: makeValuePtr("https://rutracker.org/forum/viewtopic.php?t=1")}}
+
+	_, err := sel.Rank(list, opts)
+	assert.ErrorIs(t, err, ErrUnknownCriteria)
+
+	_, err = sel.Select(list, opts)
+	assert.ErrorIs(t, err, ErrUnknownCriteria)
+
+	assert.ErrorIs(t, sel.Sort(list, opts), ErrUnknownCriteria)
+}
@@ -0,0 +1,150 @@
+package selector
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/RacoonMediaServer/rms-media-discovery/pkg/client/models"
+)
+
+// LanguageOriginal is a pseudo-language of the original audio track
+const LanguageOriginal = "original"
+
+// languageCodes are short names of the languages, they match only whole words ("Free Guy" is not French)
+var languageCodes = map[string][]string{
+	LanguageOriginal: {"orig"},
+	"rus":            {"rus"},
+	"eng":            {"eng"},
+	"ukr":            {"ukr"},
+	"jpn":            {"jpn"},
+	"kor":            {"kor"},
+	"ger":            {"ger", "deu"},
+	"fre":            {"fre", "fra"},
+	"spa":            {"spa"},
+	"ita":            {"ita"},
+	"chi":            {"chi"},
+}
+
+// languageStems match beginning of the words
+var languageStems = map[string][]string{
+	LanguageOriginal: {"original", "оригинал"},
+	"rus":            {"russian", "русск", "дубл", "многоголос", "двухголос", "одноголос", "авторск", "закадров"},
+	"eng":            {"english", "англ"},
+	"ukr":            {"ukrainian", "укр"},
+	"jpn":            {"japan", "япон"},
+	"kor":            {"korean", "корей"},
+	"ger":            {"german", "немец"},
+	"fre":            {"french", "француз"},
+	"spa":            {"spanish", "испан"},
+	"ita":            {"italian", "итальян"},
+	"chi":            {"chinese", "китай"},
+}
+
+var (
+	languageExpressions     = makeLanguageExpressions(true)
+	languageCodeExpressions = makeLanguageExpressions(false)
+)
+
+func quoteAll(tokens []string) string {
+	quoted := make([]string, len(tokens))
+	for i := range tokens {
+		quoted[i] = regexp.QuoteMeta(tokens[i])
+	}
+	return strings.Join(quoted, "|")
+}
+
+func makeLanguageExpressions(withStems bool) map[string]*regexp.Regexp {
+	result := make(map[string]*regexp.Regexp, len(languageCodes))
+	for lang, codes := range languageCodes {
+		expr := `(?i)(^|[^\p{L}])((` + quoteAll(codes) + `)([^\p{L}]|$)`
+		if withStems {
+			expr += `|(` + quoteAll(languageStems[lang]) + `)`
+		}
+		result[lang] = regexp.MustCompile(expr + `)`)
+	}
+	return result
+}
+
+// hasLanguage checks the torrent has audio track of the language. Words in the title are often a part of the name
+// ("The Italian Job"), so only language codes are searched there
+func (s selection) hasLanguage(t *models.SearchTorrentsResult, lang string) bool {
+	lang = strings.ToLower(lang)
+	if expr, ok := languageExpressions[lang]; ok && expr.MatchString(t.Voice) {
+		return true
+	}
+	if expr, ok := languageCodeExpressions[lang]; ok && expr.MatchString(getString(t.Title)) {
+		return true
+	}
+	// озвучки из списка студий всегда русские
+	return lang == "rus" && s.rankVoiceByList(strings.ToLower(getAudioText(t))) > 0
+}
+
+func getAudioText(t *models.SearchTorrentsResult) string {
+	return t.Voice + " " + getString(t.Title)
+}
+
+func (s selection) rankVoiceByList(voice string) float32 {
+	perItemWeight := ratio(1, len(s.VoiceList))
+	for j, v := range s.VoiceList {
+		for _, w := range v {
+			if strings.Contains(voice, w) {
+				return float32(len(s.VoiceList)-j) * perItemWeight
+			}
+		}
+	}
+	return 0
+}
+
+// rankByLanguage ranks audio tracks by preferred languages. Russian voice-overs are additionally ranked by studio
+func (s selection) rankByLanguage(list []*models.SearchTorrentsResult) []float32 {
+	ranks := make([]float32, len(list))
+	perLanguageWeight := ratio(1, len(s.Languages))
+	for i, t := range list {
+		for j, lang := range s.Languages {
+			if !s.hasLanguage(t, lang) {
+				continue
+			}
+			ranks[i] = float32(len(s.Languages)-j) * perLanguageWeight
+			if strings.EqualFold(lang, "rus") && len(s.VoiceList) != 0 {
+				// неизвестная студия опускает раздачу к следующему языку, но не ниже него
+				ranks[i] -= perLanguageWeight * 0.5 * (1 - s.rankVoiceByList(strings.ToLower(t.Voice)))
+			}
+			break
+		}
+		s.log().Debugf("%d rank by language: %.4f", i, ranks[i])
+	}
+	return ranks
+}
+
+func (s selection) rankBySubtitles(list []*models.SearchTorrentsResult) []float32 {
+	ranks := make([]float32, len(list))
+	if len(s.SubtitleLanguages) == 0 {
+		return ranks
+	}
+	perLanguageWeight := ratio(1, len(s.SubtitleLanguages))
+	for i, t := range list {
+		text := strings.Join(t.Subtitles, " ")
+		for j, lang := range s.SubtitleLanguages {
+			if expr, ok := languageExpressions[strings.ToLower(lang)]; ok && expr.MatchString(text) {
+				ranks[i] = float32(len(s.SubtitleLanguages)-j) * perLanguageWeight
+				break
+			}
+		}
+		s.log().Debugf("%d rank by subtitles: %.4f", i, ranks[i])
+	}
+	return ranks
+}
+
+func (s selection) limitByOriginal(list []*models.SearchTorrentsResult) []float32 {
+	ranks := make([]float32, len(list))
+	if !s.RequireOriginal {
+		return ranks
+	}
+	for i, t := range list {
+		if !s.hasLanguage(t, LanguageOriginal) {
+			ranks[i] = -1
+			s.log().Debugf("%d limit by original: %.4f", i, ranks[i])
+		}
+	}
+	return ranks
+}
@@ -0,0 +1,75 @@
+package selector
+
+import (
+	"testing"
+
+	"github.com/RacoonMediaServer/rms-media-discovery/pkg/client/models"
+	"github.com/RacoonMediaServer/rms-media-discovery/pkg/media"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMediaSelector_Languages(t *testing.T) {
+	newTorrent := func(title, voice string, subtitles ...string) *models.SearchTorrentsResult {
+		return &models.SearchTorrentsResult{
+			Title:     makeValuePtr(title),
+			Link:      makeValuePtr("https://rutracker.org/forum/viewtopic.php?t=1"),
+			Seeders:   makeValuePtr[int64](100),
+			Size:      makeValuePtr[int64](8000),
+			Quality:   "1080p",
+			Voice:     voice,
+			Subtitles: subtitles,
+		}
+	}
+	list := []*models.SearchTorrentsResult{
+		newTorrent("Dune (2021) BDRip 1080p", "Дубляж"),
+		newTorrent("Dune (2021) BDRip 1080p", "LostFilm"),
+		newTorrent("Dune (2021) BDRip 1080p", "Дубляж, Оригинал", "Russian"),
+		newTorrent("Dune (2021) BDRip 1080p | ENG", "Original", "Russian", "English"),
+	}
+
+	testCases := []struct {
+		settings Settings
+		result   int
+	}{
+		{settings: Settings{}, result: 1},
+		{settings: Settings{Languages: []string{"rus", "original"}}, result: 1},
+		{settings: Settings{Languages: []string{"original", "rus"}}, result: 2},
+		{settings: Settings{Languages: []string{"eng"}, SubtitleLanguages: []string{"eng", "rus"}}, result: 3},
+		{settings: Settings{RequireOriginal: true, SubtitleLanguages: []string{"rus"}}, result: 2},
+	}
+
+	for i, tc := range testCases {
+		tc.settings.QualityPrior = []string{"1080p"}
+		tc.settings.MaxSeasonSizeMB = 50 * 1024
+		tc.settings.VoiceList.Append("lostfilm")
+		sel := New(tc.settings)
+		assert.Equal(t, list[tc.result], mustSelect(t, sel, list, Options{Criteria: CriteriaQuality, MediaType: media.Movies}), "test case %d", i)
+	}
+}
+
+func TestHasLanguage(t *testing.T) {
+	testCases := []struct {
+		title    string
+		voice    string
+		lang     string
+		expected bool
+	}{
+		{title: "Free Guy (2021) BDRip 1080p", lang: "fre", expected: false},
+		{title: "Spaceballs (1987) WEB-DL", lang: "spa", expected: false},
+		{title: "Chicago (2002) BDRip", lang: "chi", expected: false},
+		{title: "The Italian Job (2003)", lang: "ita", expected: false},
+		{title: "The English Patient (1996)", lang: "eng", expected: false},
+		{title: "Origins (2019)", lang: LanguageOriginal, expected: false},
+		{title: "Ratatouille (2007) | FRE, ENG", lang: "fre", expected: true},
+		{title: "Dune (2021) | Rus, Eng", lang: "eng", expected: true},
+		{title: "Dune (2021)", voice: "English, Italian", lang: "ita", expected: true},
+		{title: "Dune (2021)", voice: "Оригинальная дорожка", lang: LanguageOriginal, expected: true},
+		{title: "Dune (2021)", voice: "Дубляж", lang: "rus", expected: true},
+	}
+
+	sel := selection{}
+	for i, tc := range testCases {
+		torrent := &models.SearchTorrentsResult{Title: makeValuePtr(tc.title), Voice: tc.voice}
+		assert.Equal(t, tc.expected, sel.hasLanguage(torrent, tc.lang), "test case %d", i)
+	}
+}
@@ -1,22 +1,21 @@
 package selector
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/RacoonMediaServer/rms-media-discovery/pkg/client/models"
 	"github.com/antzucaro/matchr"
 )
 
-func (s selection) getMovieRankFunction() rankFunc {
-	switch s.Criteria {
-	case CriteriaQuality:
-		return makeRankFunc(s.limitBySize, s.rankByQuality, s.rankWeight(2, s.getRankByVoiceFunc()))
-	case CriteriaFastest:
-		return makeRankFunc(s.rankBySize, s.rankBySeeders, s.rankWeight(0.5, s.getRankByVoiceFunc()))
-	case CriteriaCompact:
-		return makeRankFunc(s.limitBySize, s.rankBySeeders, s.rankByQuality, s.rankWeight(4, s.rankBySeasons), s.rankWeight(2, s.getRankByVoiceFunc()))
+func (s selection) getMoviePipeline() (Pipeline, error) {
+	if p, ok := s.Pipelines[s.Criteria]; ok && len(p) != 0 {
+		return p, nil
 	}
-	panic("unknown criteria")
+	if p, ok := defaultMoviePipelines[s.Criteria]; ok {
+		return p, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownCriteria, s.Criteria)
 }
 
 func (s selection) rankBySeasons(list []*models.SearchTorrentsResult) []float32 {
@@ -26,36 +25,31 @@ func (s selection) rankBySeasons(list []*models.SearchTorrentsResult) []float32
 	})
 
 	for i, t := range list {
-		ranks[i] = float32(len(t.Seasons)) / float32(max)
+		ranks[i] = ratio(len(t.Seasons), max)
 		s.log().Debugf("%d rank by seasons: %.4f", i, ranks[i])
 	}
 
 	return ranks
 }
 
-func (s selection) getRankByVoiceFunc() rankFunc {
+func (s selection) getRankByVoiceFunc() RankFunc {
 	return func(list []*models.SearchTorrentsResult) []float32 {
-		if s.Voice == "" {
-			return s.rankByVoiceList(list)
+		if s.Voice != "" {
+			return s.rankByVoice(list)
+		}
+		if len(s.Languages) != 0 {
+			return s.rankByLanguage(list)
 		}
-		return s.rankByVoice(list)
+		return s.rankByVoiceList(list)
 	}
 }
 
 func (s selection) rankByVoiceList(list []*models.SearchTorrentsResult) []float32 {
 	ranks := make([]float32, len(list))
-	perItemWeight := 1 / float32(len(s.VoiceList))
 	for i, t := range list {
-		tVoice := strings.ToLower(t.Voice)
-	ScanVoice:
-		for j, voice := range s.VoiceList {
-			for _, w := range voice {
-				if strings.Index(tVoice, w) >= 0 {
-					ranks[i] = float32(len(s.VoiceList)-j) * perItemWeight
-					s.log().Debugf("%d rank by voice list: %.4f", i, ranks[i])
-					break ScanVoice
-				}
-			}
+		ranks[i] = s.rankVoiceByList(strings.ToLower(t.Voice))
+		if ranks[i] != 0 {
+			s.log().Debugf("%d rank by voice list: %.4f", i, ranks[i])
 		}
 	}
 	return ranks
@@ -75,7 +69,7 @@ func (s selection) rankByVoice(list []*models.SearchTorrentsResult) []float32 {
 		return elem
 	})
 	for j, d := range distance {
-		ranks[j] = 1 - (float32(d) / float32(max))
+		ranks[j] = 1 - ratio(d, max)
 		s.log().Debugf("%d rank by voice: %.4f", j, ranks[j])
 	}
 	return ranks
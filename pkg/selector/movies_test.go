@@ -1,6 +1,7 @@
 package selector
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/RacoonMediaServer/rms-media-discovery/pkg/client/models"
@@ -16,6 +17,12 @@ type testCase struct {
 	voice    string
 }
 
+func mustSelect(t *testing.T, sel MediaSelector, list []*models.SearchTorrentsResult, opts Options) *models.SearchTorrentsResult {
+	result, err := sel.Select(list, opts)
+	assert.NoError(t, err)
+	return result
+}
+
 func makeValuePtr[T any](val T) *T {
 	p := new(T)
 	*p = val
@@ -131,6 +138,17 @@ var testCases = []testCase{
 	},
 }
 
+func init() {
+	// кандидаты без ссылки отбрасываются фильтром
+	for i := range testCases {
+		for j, t := range testCases[i].list {
+			if t != nil && t.Link == nil {
+				t.Link = makeValuePtr(fmt.Sprintf("https://rutracker.org/forum/viewtopic.php?t=%d", j))
+			}
+		}
+	}
+}
+
 func TestMediaSelector_Select(t *testing.T) {
 	s := Settings{
 		MinSeasonSizeMB:     1024,
@@ -157,7 +175,36 @@ func TestMediaSelector_Select(t *testing.T) {
 			Criteria:  test.criteria,
 			MediaType: media.Movies,
 		}
-		result := sel.Select(test.list, opts)
+		result, err := sel.Select(test.list, opts)
+		assert.NoError(t, err)
 		assert.Equal(t, test.list[test.result], result, "test case %d failed", i)
 	}
 }
+
+func TestMediaSelector_Explain(t *testing.T) {
+	s := Settings{
+		MinSeasonSizeMB:     1024,
+		MaxSeasonSizeMB:     1024 * 50,
+		MinSeedersThreshold: 50,
+		QualityPrior:        []string{"1080p", "720p", "480p"},
+	}
+	s.VoiceList.Append("сыендук")
+	sel := New(s)
+
+	for i, test := range testCases {
+		opts := Options{Criteria: test.criteria, MediaType: media.Movies}
+		ranks, err := sel.Rank(test.list, opts)
+		assert.NoError(t, err, "test case %d failed", i)
+		explanations, err := sel.Explain(test.list, opts)
+		assert.NoError(t, err, "test case %d failed", i)
+		assert.Equal(t, len(ranks), len(explanations), "test case %d failed", i)
+		for j := range ranks {
+			var total float32
+			for _, score := range explanations[j].Scores {
+				total += score.Weight * score.Rank
+			}
+			assert.InDelta(t, ranks[j], explanations[j].Total, 1e-6, "test case %d failed", i)
+			assert.InDelta(t, total, explanations[j].Total, 1e-6, "test case %d failed", i)
+		}
+	}
+}
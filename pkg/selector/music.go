@@ -6,27 +6,38 @@ import (
 	"github.com/antzucaro/matchr"
 )
 
-func (s selection) getMusicRankFunction() rankFunc {
-	funcs := []rankFunc{s.rankBySeeders}
-	funcs = append(funcs, s.getRankByTextFunc()...)
-	return makeRankFunc(funcs...)
+var musicPipeline = Pipeline{
+	{Name: "rankBySeeders", Weight: 1},
+	{Name: "rankByText", Weight: 1},
 }
 
-func (s selection) getRankByTextFunc() []rankFunc {
-	var result []rankFunc
+var discographyPipeline = Pipeline{
+	{Name: "rankBySeeders", Weight: 1},
+	{Name: "rankByDiscography", Weight: 1},
+}
+
+func (s selection) getMusicPipeline() Pipeline {
 	if s.Discography {
-		result = append(result, func(list []*models.SearchTorrentsResult) []float32 {
-			return s.rankByText(s.Query+" дискография", list)
-		})
-		result = append(result, func(list []*models.SearchTorrentsResult) []float32 {
-			return s.rankByText(s.Query+" discography", list)
-		})
-	} else {
-		result = append(result, func(list []*models.SearchTorrentsResult) []float32 {
-			return s.rankByText(s.Query, list)
-		})
+		return discographyPipeline
+	}
+	return musicPipeline
+}
+
+func (s selection) getRankByTextFunc() RankFunc {
+	return func(list []*models.SearchTorrentsResult) []float32 {
+		return s.rankByText(s.Query, list)
+	}
+}
+
+// getRankByDiscographyFunc ranks titles by both russian and english name of discography
+func (s selection) getRankByDiscographyFunc() RankFunc {
+	return func(list []*models.SearchTorrentsResult) []float32 {
+		ranks := s.rankByText(s.Query+" дискография", list)
+		for i, r := range s.rankByText(s.Query+" discography", list) {
+			ranks[i] += r
+		}
+		return ranks
 	}
-	return result
 }
 
 func (s selection) rankByText(query string, list []*models.SearchTorrentsResult) []float32 {
@@ -35,7 +46,7 @@ func (s selection) rankByText(query string, list []*models.SearchTorrentsResult)
 
 	target := heuristic.NormalizeWithoutBraces(query)
 	for i, t := range list {
-		title := heuristic.NormalizeWithoutBraces(*t.Title)
+		title := heuristic.NormalizeWithoutBraces(getString(t.Title))
 		distance[i] = matchr.Levenshtein(title, target)
 	}
 
@@ -43,7 +54,7 @@ func (s selection) rankByText(query string, list []*models.SearchTorrentsResult)
 		return elem
 	})
 	for j, d := range distance {
-		ranks[j] = 1 - (float32(d) / float32(max))
+		ranks[j] = 1 - ratio(d, max)
 	}
 	return ranks
 }
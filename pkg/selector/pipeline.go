@@ -0,0 +1,180 @@
+package selector
+
+import (
+	"fmt"
+
+	"github.com/RacoonMediaServer/rms-media-discovery/pkg/client/models"
+)
+
+// RankStep is a named rank function with weight in the ranking pipeline
+type RankStep struct {
+	// Name of built-in or custom rank function
+	Name string
+
+	// Weight of the rank. Zero means 1
+	Weight float32
+}
+
+// Pipeline is a declarative list of rank functions, which are summed up to total rank
+type Pipeline []RankStep
+
+// TrackerReputation is a reputation of the tracker in range [0; 1]
+type TrackerReputation struct {
+	// Tracker is a part of torrent link, e.g. host of the tracker
+	Tracker string
+
+	Reputation float32
+}
+
+var builtinRankers = map[string]func(s selection) RankFunc{
+	"limitBySize":   func(s selection) RankFunc { return s.limitBySize },
+	"rankBySize":    func(s selection) RankFunc { return s.rankBySize },
+	"rankByQuality": func(s selection) RankFunc { return s.rankByQuality },
+	"rankBySeeders": func(s selection) RankFunc { return s.rankBySeeders },
+	"rankBySeasons": func(s selection) RankFunc { return s.rankBySeasons },
+	"rankByVoice":   func(s selection) RankFunc { return s.getRankByVoiceFunc() },
+	"rankByCodec":   func(s selection) RankFunc { return s.rankByCodec },
+	"rankByHDR":     func(s selection) RankFunc { return s.rankByHDR },
+	"rankByTracker": func(s selection) RankFunc { return s.rankByTracker },
+	"rankByAge":     func(s selection) RankFunc { return s.rankByAge },
+
+	"rankByEfficiency": func(s selection) RankFunc { return s.rankByEfficiency },
+	"limitByQuality":   func(s selection) RankFunc { return s.limitByQuality },
+	"limitByVoice":     func(s selection) RankFunc { return s.limitByVoice },
+
+	"rankByLanguage":  func(s selection) RankFunc { return s.rankByLanguage },
+	"rankBySubtitles": func(s selection) RankFunc { return s.rankBySubtitles },
+	"limitByOriginal": func(s selection) RankFunc { return s.limitByOriginal },
+
+	"rankByText":        func(s selection) RankFunc { return s.getRankByTextFunc() },
+	"rankByDiscography": func(s selection) RankFunc { return s.getRankByDiscographyFunc() },
+}
+
+var defaultMoviePipelines = map[Criteria]Pipeline{
+	CriteriaQuality: {
+		{Name: "limitBySize", Weight: 1},
+		{Name: "rankByQuality", Weight: 1},
+		{Name: "rankByVoice", Weight: 2},
+		{Name: "rankBySubtitles", Weight: 0.5},
+		{Name: "limitByOriginal", Weight: 10},
+	},
+	CriteriaFastest: {
+		{Name: "rankBySize", Weight: 1},
+		{Name: "rankBySeeders", Weight: 1},
+		{Name: "rankByVoice", Weight: 0.5},
+		{Name: "rankBySubtitles", Weight: 0.5},
+		{Name: "limitByOriginal", Weight: 10},
+	},
+	CriteriaCompact: {
+		{Name: "limitBySize", Weight: 1},
+		{Name: "rankBySeeders", Weight: 1},
+		{Name: "rankByQuality", Weight: 1},
+		{Name: "rankBySeasons", Weight: 4},
+		{Name: "rankByVoice", Weight: 2},
+		{Name: "rankBySubtitles", Weight: 0.5},
+		{Name: "limitByOriginal", Weight: 10},
+	},
+	CriteriaBalanced: {
+		{Name: "limitBySize", Weight: 1},
+		{Name: "rankByEfficiency", Weight: 2},
+		{Name: "rankByQuality", Weight: 1},
+		{Name: "rankBySeeders", Weight: 0.5},
+		{Name: "rankByVoice", Weight: 2},
+		{Name: "rankBySubtitles", Weight: 0.5},
+		{Name: "limitByOriginal", Weight: 10},
+	},
+	CriteriaSmallest: {
+		{Name: "limitBySize", Weight: 1},
+		{Name: "limitByQuality", Weight: 2},
+		{Name: "limitByVoice", Weight: 1},
+		{Name: "rankBySize", Weight: 2},
+		{Name: "rankBySeeders", Weight: 0.25},
+		{Name: "rankBySubtitles", Weight: 0.5},
+		{Name: "limitByOriginal", Weight: 10},
+	},
+}
+
+// DefaultPipeline returns built-in pipeline of movies ranking for the criteria
+func DefaultPipeline(criteria Criteria) Pipeline {
+	return append(Pipeline{}, defaultMoviePipelines[criteria]...)
+}
+
+// Validate checks all rank functions of the pipeline are known
+func (p Pipeline) Validate(custom map[string]RankFunc) error {
+	if len(p) == 0 {
+		return fmt.Errorf("pipeline is empty")
+	}
+	for _, step := range p {
+		_, builtin := builtinRankers[step.Name]
+		_, found := custom[step.Name]
+		if !builtin && !found {
+			return fmt.Errorf("unknown rank function: %s", step.Name)
+		}
+	}
+	return nil
+}
+
+// ParsePipelines converts pipelines keyed by criteria name and validates them
+func ParsePipelines(pipelines map[string]Pipeline, custom map[string]RankFunc) (map[Criteria]Pipeline, error) {
+	result := make(map[Criteria]Pipeline, len(pipelines))
+	for name, p := range pipelines {
+		criteria, err := ParseCriteria(name)
+		if err != nil {
+			return nil, err
+		}
+		if err = p.Validate(custom); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		result[criteria] = p
+	}
+	return result, nil
+}
+
+func (s selection) getRankFunc(name string) RankFunc {
+	if fn, ok := s.Rankers[name]; ok {
+		return fn
+	}
+	if fn, ok := builtinRankers[name]; ok {
+		return fn(s)
+	}
+	return nil
+}
+
+// scores runs the pipeline over the list. Empty items are not ranked
+func (s selection) scores(p Pipeline, list []*models.SearchTorrentsResult) []Explanation {
+	result := make([]Explanation, len(list))
+
+	items := make([]*models.SearchTorrentsResult, 0, len(list))
+	indexes := make([]int, 0, len(list))
+	for i, t := range list {
+		if t != nil {
+			items = append(items, t)
+			indexes = append(indexes, i)
+		}
+	}
+	if len(items) == 0 {
+		return result
+	}
+
+	for _, step := range p {
+		fn := s.getRankFunc(step.Name)
+		if fn == nil {
+			s.log().Warnf("Unknown rank function: %s", step.Name)
+			continue
+		}
+		weight := step.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		fRanks := fn(items)
+		for j, i := range indexes {
+			var rank float32
+			if j < len(fRanks) && isFinite(fRanks[j]) {
+				rank = fRanks[j]
+			}
+			result[i].Scores = append(result[i].Scores, Score{Name: step.Name, Weight: weight, Rank: rank})
+			result[i].Total += weight * rank
+		}
+	}
+	return result
+}
@@ -0,0 +1,111 @@
+package selector
+
+import (
+	"testing"
+
+	"github.com/RacoonMediaServer/rms-media-discovery/pkg/client/models"
+	"github.com/RacoonMediaServer/rms-media-discovery/pkg/media"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePipelines(t *testing.T) {
+	pipelines, err := ParsePipelines(map[string]Pipeline{
+		"Quality": {{Name: "rankByQuality"}, {Name: "rankByCodec", Weight: 0.5}},
+	}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, pipelines[CriteriaQuality], 2)
+
+	_, err = ParsePipelines(map[string]Pipeline{"best": {{Name: "rankByQuality"}}}, nil)
+	assert.Error(t, err)
+
+	_, err = ParsePipelines(map[string]Pipeline{"quality": {{Name: "rankByMagic"}}}, nil)
+	assert.Error(t, err)
+
+	custom := map[string]RankFunc{"rankByMagic": func(list []*models.SearchTorrentsResult) []float32 {
+		return make([]float32, len(list))
+	}}
+	_, err = ParsePipelines(map[string]Pipeline{"quality": {{Name: "rankByMagic"}}}, custom)
+	assert.NoError(t, err)
+}
+
+func TestMediaSelector_Pipeline(t *testing.T) {
+	list := []*models.SearchTorrentsResult{
+		{
+			Title:   makeValuePtr("Dune (2021) WEB-DL 2160p x264"),
+			Link:    makeValuePtr("https://rutracker.org/forum/viewtopic.php?t=100"),
+			Seeders: makeValuePtr[int64](100),
+			Size:    makeValuePtr[int64](10000),
+		},
+		{
+			Title:   makeValuePtr("Dune (2021) WEB-DL 2160p HEVC HDR10"),
+			Link:    makeValuePtr("https://rutracker.org/forum/viewtopic.php?t=50"),
+			Seeders: makeValuePtr[int64](100),
+			Size:    makeValuePtr[int64](10000),
+		},
+		{
+			Title:   makeValuePtr("Dune (2021) WEB-DL 2160p AV1"),
+			Link:    makeValuePtr("https://kinozal.tv/details.php?id=7"),
+			Seeders: makeValuePtr[int64](100),
+			Size:    makeValuePtr[int64](10000),
+		},
+	}
+	opts := Options{Criteria: CriteriaQuality, MediaType: media.Movies}
+
+	testCases := []struct {
+		pipeline Pipeline
+		settings Settings
+		result   int
+	}{
+		{pipeline: Pipeline{{Name: "rankByCodec"}}, result: 2},
+		{pipeline: Pipeline{{Name: "rankByCodec"}}, settings: Settings{CodecPrior: []string{"hevc", "avc"}}, result: 1},
+		{pipeline: Pipeline{{Name: "rankByHDR"}}, result: 1},
+		{pipeline: Pipeline{{Name: "rankByAge"}}, result: 0},
+		{
+			pipeline: Pipeline{{Name: "rankByTracker"}},
+			settings: Settings{Trackers: []TrackerReputation{{Tracker: "kinozal", Reputation: 1}, {Tracker: "rutracker", Reputation: 0.5}}},
+			result:   2,
+		},
+		{
+			pipeline: Pipeline{{Name: "rankByHDR", Weight: 2}, {Name: "rankByCodec"}},
+			result:   1,
+		},
+	}
+
+	for i, tc := range testCases {
+		tc.settings.Pipelines = map[Criteria]Pipeline{CriteriaQuality: tc.pipeline}
+		sel := New(tc.settings)
+		assert.Equal(t, list[tc.result], mustSelect(t, sel, list, opts), "test case %d", i)
+	}
+}
+
+func TestMediaSelector_StorageCriteria(t *testing.T) {
+	newTorrent := func(quality, voice string, size int64) *models.SearchTorrentsResult {
+		return &models.SearchTorrentsResult{
+			Title:   makeValuePtr("Dune (2021) " + quality),
+			Link:    makeValuePtr("https://rutracker.org/forum/viewtopic.php?t=1"),
+			Seeders: makeValuePtr[int64](100),
+			Size:    makeValuePtr(size),
+			Quality: quality,
+			Voice:   voice,
+		}
+	}
+	list := []*models.SearchTorrentsResult{
+		newTorrent("1080p", "Дубляж", 40000),
+		newTorrent("1080p", "Дубляж", 10000),
+		newTorrent("720p", "Дубляж", 4000),
+		newTorrent("480p", "Дубляж", 1500),
+		newTorrent("720p", "Original", 3000),
+	}
+	settings := Settings{
+		MinSeasonSizeMB:     1024,
+		MaxSeasonSizeMB:     50 * 1024,
+		MinSeedersThreshold: 50,
+		QualityPrior:        []string{"1080p", "720p", "480p"},
+		Voice:               "дубляж",
+		MinQuality:          "720p",
+	}
+	sel := New(settings)
+
+	assert.Equal(t, list[1], mustSelect(t, sel, list, Options{Criteria: CriteriaBalanced, MediaType: media.Movies}))
+	assert.Equal(t, list[2], mustSelect(t, sel, list, Options{Criteria: CriteriaSmallest, MediaType: media.Movies}))
+}
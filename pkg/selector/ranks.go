@@ -1,19 +1,15 @@
 package selector
 
 import (
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
 	"github.com/RacoonMediaServer/rms-media-discovery/pkg/client/models"
 )
 
-func (s selection) rankWeight(weight float32, f rankFunc) rankFunc {
-	return func(list []*models.SearchTorrentsResult) []float32 {
-		ranks := f(list)
-		for i := range ranks {
-			ranks[i] = weight * ranks[i]
-		}
-		return ranks
-	}
-}
-
 func (s selection) rankBySize(list []*models.SearchTorrentsResult) []float32 {
 	ranks := make([]float32, len(list))
 	_, max, _ := findMax(list, func(t *models.SearchTorrentsResult) int64 {
@@ -21,7 +17,7 @@ func (s selection) rankBySize(list []*models.SearchTorrentsResult) []float32 {
 	})
 
 	for i, t := range list {
-		ranks[i] = 1 - (float32(getValue(t.Size)) / float32(max))
+		ranks[i] = 1 - ratio(getValue(t.Size), max)
 		s.log().Debugf("%d rank by size: %.4f", i, ranks[i])
 	}
 	return ranks
@@ -55,7 +51,7 @@ func (s selection) rankBySeeders(list []*models.SearchTorrentsResult) []float32
 	for i, t := range list {
 		seeders := getValue(t.Seeders)
 		if seeders < s.MinSeedersThreshold {
-			ranks[i] = float32(seeders) / float32(max)
+			ranks[i] = ratio(seeders, max)
 		} else {
 			ranks[i] = 1
 		}
@@ -66,7 +62,7 @@ func (s selection) rankBySeeders(list []*models.SearchTorrentsResult) []float32
 
 func (s selection) rankByQuality(list []*models.SearchTorrentsResult) []float32 {
 	ranks := make([]float32, len(list))
-	perQualityWeight := 1 / float32(len(s.QualityPrior))
+	perQualityWeight := ratio(1, len(s.QualityPrior))
 	for i, t := range list {
 		for j, q := range s.QualityPrior {
 			if t.Quality == q {
@@ -79,3 +75,203 @@ func (s selection) rankByQuality(list []*models.SearchTorrentsResult) []float32
 
 	return ranks
 }
+
+var defaultCodecPrior = []string{"av1", "hevc", "avc"}
+
+var codecExpressions = map[string]*regexp.Regexp{
+	"av1":  regexp.MustCompile(`(?i)\bav1\b`),
+	"hevc": regexp.MustCompile(`(?i)\b(hevc|[hx]\.?265)\b`),
+	"avc":  regexp.MustCompile(`(?i)\b(avc|[hx]\.?264)\b`),
+}
+
+var hdrExpression = regexp.MustCompile(`(?i)\b(hdr(10\+?)?|dolby\s*vision|dovi|dv)\b`)
+
+var topicIdExpression = regexp.MustCompile(`(\d+)\D*$`)
+
+func getReleaseText(t *models.SearchTorrentsResult) string {
+	return getString(t.Title) + " " + t.Format + " " + t.Rip
+}
+
+func (s selection) rankByCodec(list []*models.SearchTorrentsResult) []float32 {
+	prior := s.CodecPrior
+	if len(prior) == 0 {
+		prior = defaultCodecPrior
+	}
+	ranks := make([]float32, len(list))
+	perCodecWeight := ratio(1, len(prior))
+	for i, t := range list {
+		text := getReleaseText(t)
+		for j, codec := range prior {
+			if expr, ok := codecExpressions[strings.ToLower(codec)]; ok && expr.MatchString(text) {
+				ranks[i] = float32(len(prior)-j) * perCodecWeight
+				break
+			}
+		}
+		s.log().Debugf("%d rank by codec: %.4f", i, ranks[i])
+	}
+	return ranks
+}
+
+func (s selection) rankByHDR(list []*models.SearchTorrentsResult) []float32 {
+	ranks := make([]float32, len(list))
+	for i, t := range list {
+		if hdrExpression.MatchString(getReleaseText(t)) {
+			ranks[i] = 1
+		}
+		s.log().Debugf("%d rank by hdr: %.4f", i, ranks[i])
+	}
+	return ranks
+}
+
+func (s selection) rankByTracker(list []*models.SearchTorrentsResult) []float32 {
+	ranks := make([]float32, len(list))
+	for i, t := range list {
+		link := strings.ToLower(getString(t.Link))
+		for _, tr := range s.Trackers {
+			if tr.Tracker != "" && strings.Contains(link, strings.ToLower(tr.Tracker)) {
+				ranks[i] = tr.Reputation
+				break
+			}
+		}
+		s.log().Debugf("%d rank by tracker: %.4f", i, ranks[i])
+	}
+	return ranks
+}
+
+func getTopic(t *models.SearchTorrentsResult) (tracker string, id int64, ok bool) {
+	u, err := url.Parse(getString(t.Link))
+	if err != nil {
+		return
+	}
+	matches := topicIdExpression.FindStringSubmatch(u.RequestURI())
+	if len(matches) < 2 {
+		return
+	}
+	id, err = strconv.ParseInt(matches[1], 10, 64)
+	return u.Host, id, err == nil
+}
+
+// rankByAge prefers fresh releases. Search results have no publication date, but trackers assign topic IDs
+// incrementally, so the newest release has the greatest ID among releases of the same tracker
+func (s selection) rankByAge(list []*models.SearchTorrentsResult) []float32 {
+	type bounds struct{ min, max int64 }
+	ranks := make([]float32, len(list))
+	trackers := map[string]*bounds{}
+	for _, t := range list {
+		tracker, id, ok := getTopic(t)
+		if !ok {
+			continue
+		}
+		b, found := trackers[tracker]
+		if !found {
+			trackers[tracker] = &bounds{min: id, max: id}
+			continue
+		}
+		b.min = min(b.min, id)
+		b.max = max(b.max, id)
+	}
+
+	for i, t := range list {
+		tracker, id, ok := getTopic(t)
+		if ok {
+			if b := trackers[tracker]; b.max != b.min {
+				ranks[i] = ratio(id-b.min, b.max-b.min)
+			}
+		}
+		s.log().Debugf("%d rank by age: %.4f", i, ranks[i])
+	}
+	return ranks
+}
+
+func (s selection) getQualityIndex(quality string) int {
+	for i, q := range s.QualityPrior {
+		if strings.EqualFold(q, quality) {
+			return i
+		}
+	}
+	return -1
+}
+
+func getUnitSize(t *models.SearchTorrentsResult) float64 {
+	units := len(t.Seasons)
+	if units == 0 {
+		units = 1
+	}
+	return float64(getValue(t.Size)) / float64(units)
+}
+
+// rankByEfficiency ranks quality per size of season (or film). Quality is squared, so better quality wins over
+// moderate growth of size, but the same quality of smaller size is always preferred
+func (s selection) rankByEfficiency(list []*models.SearchTorrentsResult) []float32 {
+	ranks := make([]float32, len(list))
+	quality := s.rankByQuality(list)
+	_, maxSize, _ := findMax(list, getUnitSize)
+	if maxSize <= 0 {
+		return ranks
+	}
+
+	efficiency := make([]float64, len(list))
+	for i, t := range list {
+		size := getUnitSize(t) / maxSize
+		if size > 0 {
+			efficiency[i] = float64(quality[i]*quality[i]) / math.Sqrt(size)
+		}
+	}
+
+	_, maxEfficiency, _ := findMax(efficiency, func(elem float64) float64 {
+		return elem
+	})
+	for i := range efficiency {
+		ranks[i] = ratio(efficiency[i], maxEfficiency)
+		s.log().Debugf("%d rank by efficiency: %.4f", i, ranks[i])
+	}
+	return ranks
+}
+
+func (s selection) limitByQuality(list []*models.SearchTorrentsResult) []float32 {
+	ranks := make([]float32, len(list))
+	if s.MinQuality == "" {
+		return ranks
+	}
+	minIndex := s.getQualityIndex(s.MinQuality)
+	for i, t := range list {
+		index := s.getQualityIndex(t.Quality)
+		if index < 0 || (minIndex >= 0 && index > minIndex) {
+			ranks[i] = -1
+			s.log().Debugf("%d limit by quality: %.4f", i, ranks[i])
+		}
+	}
+	return ranks
+}
+
+func (s selection) limitByVoice(list []*models.SearchTorrentsResult) []float32 {
+	ranks := make([]float32, len(list))
+	if s.Voice == "" && len(s.VoiceList) == 0 && len(s.Languages) == 0 {
+		return ranks
+	}
+	target := strings.ToLower(s.Voice)
+	for i, t := range list {
+		voice := strings.ToLower(t.Voice)
+		acceptable := false
+		if target != "" {
+			acceptable = strings.Contains(voice, target)
+		} else if len(s.Languages) != 0 {
+			for _, lang := range s.Languages {
+				acceptable = acceptable || s.hasLanguage(t, lang)
+			}
+		} else {
+			for _, v := range s.VoiceList {
+				for _, w := range v {
+					if strings.Contains(voice, w) {
+						acceptable = true
+					}
+				}
+			}
+		}
+		if !acceptable {
+			ranks[i] = -1
+			s.log().Debugf("%d limit by voice: %.4f", i, ranks[i])
+		}
+	}
+	return ranks
+}
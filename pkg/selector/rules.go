@@ -0,0 +1,196 @@
+package selector
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/RacoonMediaServer/rms-media-discovery/pkg/client/models"
+)
+
+// Rule describes conditions of matching the torrent. All non-empty conditions must be satisfied
+type Rule struct {
+	// Title is a regular expression for the torrent title (case-insensitive)
+	Title string
+
+	// Voice is a regular expression for the voice-over (case-insensitive)
+	Voice string
+
+	// Keywords match when any of them is presented in the title or the voice-over
+	Keywords []string
+
+	// Qualities match when the torrent quality is one of them
+	Qualities []string
+
+	// MinSizeMB and MaxSizeMB limit size of the torrent
+	MinSizeMB int64
+	MaxSizeMB int64
+
+	// Trackers match when the torrent link contains any of them
+	Trackers []string
+
+	// Links match exact torrent links
+	Links []string
+}
+
+// Rules are applied to the candidates before ranking
+type Rules struct {
+	// Block excludes the candidates, which match any rule
+	Block []Rule
+
+	// Allow leaves only the candidates, which match any rule. Empty list allows everything
+	Allow []Rule
+}
+
+// Merge combines rules
+func (r Rules) Merge(other Rules) Rules {
+	result := Rules{}
+	result.Block = append(append(result.Block, r.Block...), other.Block...)
+	result.Allow = append(append(result.Allow, r.Allow...), other.Allow...)
+	return result
+}
+
+// IsEmpty checks whether any rules are presented
+func (r Rules) IsEmpty() bool {
+	return len(r.Block) == 0 && len(r.Allow) == 0
+}
+
+func compileRegexp(expr string) (*regexp.Regexp, error) {
+	if expr == "" {
+		return nil, nil
+	}
+	return regexp.Compile("(?i)" + expr)
+}
+
+func matchRegexp(re *regexp.Regexp, s string) bool {
+	return re == nil || re.MatchString(s)
+}
+
+func containsAny(s string, words []string) bool {
+	s = strings.ToLower(s)
+	for _, w := range words {
+		if strings.Contains(s, strings.ToLower(w)) {
+			return true
+		}
+	}
+	return false
+}
+
+// compiledRule is a rule with prepared regular expressions
+type compiledRule struct {
+	Rule
+	title *regexp.Regexp
+	voice *regexp.Regexp
+}
+
+func (r Rule) compile() (compiledRule, error) {
+	var err error
+	result := compiledRule{Rule: r}
+	if result.title, err = compileRegexp(r.Title); err != nil {
+		return result, fmt.Errorf("invalid title expression: %w", err)
+	}
+	if result.voice, err = compileRegexp(r.Voice); err != nil {
+		return result, fmt.Errorf("invalid voice expression: %w", err)
+	}
+	return result, nil
+}
+
+func (r compiledRule) match(t *models.SearchTorrentsResult) bool {
+	title := getString(t.Title)
+	link := getString(t.Link)
+	size := getValue(t.Size)
+
+	if !matchRegexp(r.title, title) || !matchRegexp(r.voice, t.Voice) {
+		return false
+	}
+	if len(r.Keywords) != 0 && !containsAny(title+" "+t.Voice, r.Keywords) {
+		return false
+	}
+	if len(r.Qualities) != 0 && !containsAny(t.Quality, r.Qualities) {
+		return false
+	}
+	if (r.MinSizeMB != 0 && size < r.MinSizeMB) || (r.MaxSizeMB != 0 && size > r.MaxSizeMB) {
+		return false
+	}
+	if len(r.Trackers) != 0 && !containsAny(link, r.Trackers) {
+		return false
+	}
+	if len(r.Links) != 0 && !slices.Contains(r.Links, link) {
+		return false
+	}
+	return true
+}
+
+// compiledRules are rules prepared for matching many candidates
+type compiledRules struct {
+	block []compiledRule
+	allow []compiledRule
+}
+
+// compile prepares the rules. Invalid rules are skipped, so one bad rule doesn't block all candidates
+func (r Rules) compile() (compiledRules, error) {
+	result := compiledRules{}
+	var errs []error
+	for _, rule := range r.Block {
+		if c, err := rule.compile(); err == nil {
+			result.block = append(result.block, c)
+		} else {
+			errs = append(errs, err)
+		}
+	}
+	for _, rule := range r.Allow {
+		if c, err := rule.compile(); err == nil {
+			result.allow = append(result.allow, c)
+		} else {
+			errs = append(errs, err)
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+func matchAny(rules []compiledRule, t *models.SearchTorrentsResult) bool {
+	for _, r := range rules {
+		if r.match(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r compiledRules) allowed(t *models.SearchTorrentsResult) bool {
+	if matchAny(r.block, t) {
+		return false
+	}
+	return len(r.allow) == 0 || matchAny(r.allow, t)
+}
+
+// IsEmpty checks whether the rule has no conditions (so matches any torrent)
+func (r Rule) IsEmpty() bool {
+	return r.Title == "" && r.Voice == "" && len(r.Keywords) == 0 && len(r.Qualities) == 0 &&
+		r.MinSizeMB == 0 && r.MaxSizeMB == 0 && len(r.Trackers) == 0 && len(r.Links) == 0
+}
+
+func (r Rule) validate() error {
+	if r.IsEmpty() {
+		return errors.New("rule has no conditions")
+	}
+	if _, err := r.compile(); err != nil {
+		return err
+	}
+	if r.MaxSizeMB != 0 && r.MinSizeMB > r.MaxSizeMB {
+		return errors.New("invalid size limits")
+	}
+	return nil
+}
+
+// Validate checks the rules are well-formed
+func (r Rules) Validate() error {
+	for _, rule := range append(append([]Rule{}, r.Block...), r.Allow...) {
+		if err := rule.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
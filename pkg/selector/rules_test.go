@@ -0,0 +1,65 @@
+package selector
+
+import (
+	"testing"
+
+	"github.com/RacoonMediaServer/rms-media-discovery/pkg/client/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompiledRules_Allowed(t *testing.T) {
+	torrent := &models.SearchTorrentsResult{
+		Title:   makeValuePtr("Dune (2021) BDRip 1080p CAMRip"),
+		Link:    makeValuePtr("https://rutracker.org/forum/viewtopic.php?t=1"),
+		Size:    makeValuePtr[int64](8000),
+		Quality: "1080p",
+		Voice:   "Дубляж",
+	}
+
+	testCases := []struct {
+		rules   Rules
+		allowed bool
+	}{
+		{rules: Rules{}, allowed: true},
+		{rules: Rules{Block: []Rule{{Keywords: []string{"camrip"}}}}, allowed: false},
+		{rules: Rules{Block: []Rule{{Keywords: []string{"camrip"}, Qualities: []string{"720p"}}}}, allowed: true},
+		{rules: Rules{Block: []Rule{{Title: `\bTS\b`}}}, allowed: true},
+		{rules: Rules{Block: []Rule{{MaxSizeMB: 4000}}}, allowed: true},
+		{rules: Rules{Block: []Rule{{MinSizeMB: 4000}}}, allowed: false},
+		{rules: Rules{Block: []Rule{{Links: []string{"https://rutracker.org/forum/viewtopic.php?t=1"}}}}, allowed: false},
+		{rules: Rules{Allow: []Rule{{Trackers: []string{"kinozal"}}}}, allowed: false},
+		{rules: Rules{Allow: []Rule{{Trackers: []string{"kinozal"}}, {Voice: "дубл"}}}, allowed: true},
+		{
+			rules:   Rules{Block: []Rule{{Voice: "дубл"}}, Allow: []Rule{{Trackers: []string{"rutracker"}}}},
+			allowed: false,
+		},
+	}
+
+	for i, tc := range testCases {
+		compiled, err := tc.rules.compile()
+		assert.NoError(t, err, "test case %d", i)
+		assert.Equal(t, tc.allowed, compiled.allowed(torrent), "test case %d", i)
+	}
+}
+
+func TestRules_Validate(t *testing.T) {
+	assert.NoError(t, Rules{Block: []Rule{{Title: "cam(rip)?"}}}.Validate())
+	assert.Error(t, Rules{Block: []Rule{{}}}.Validate())
+	assert.Error(t, Rules{Allow: []Rule{{Title: "cam("}}}.Validate())
+	assert.Error(t, Rules{Block: []Rule{{MinSizeMB: 10, MaxSizeMB: 5}}}.Validate())
+}
+
+func TestRules_InvalidRuleIgnored(t *testing.T) {
+	torrent := &models.SearchTorrentsResult{
+		Title: makeValuePtr("Dune (2021) BDRip 1080p"),
+		Link:  makeValuePtr("https://rutracker.org/forum/viewtopic.php?t=1"),
+		Voice: "Дубляж",
+	}
+
+	compiled, err := Rules{Allow: []Rule{{Title: "cam("}, {Voice: "дубл"}}}.compile()
+	assert.Error(t, err)
+	assert.True(t, compiled.allowed(torrent))
+
+	sel := New(Settings{Rules: Rules{Allow: []Rule{{Title: "cam("}}}})
+	assert.Len(t, sel.Filter([]*models.SearchTorrentsResult{torrent}, Options{}), 1)
+}
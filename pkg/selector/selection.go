@@ -1,6 +1,7 @@
 package selector
 
 import (
+	"github.com/RacoonMediaServer/rms-media-discovery/pkg/client/models"
 	"github.com/RacoonMediaServer/rms-media-discovery/pkg/media"
 	"github.com/apex/log"
 )
@@ -10,17 +11,25 @@ type selection struct {
 	Options
 }
 
-func (s selection) getRankFunction() rankFunc {
+func (s selection) getPipeline() (Pipeline, error) {
 	switch s.MediaType {
 	case media.Movies:
-		return s.getMovieRankFunction()
+		return s.getMoviePipeline()
 	case media.Music:
-		return s.getMusicRankFunction()
+		return s.getMusicPipeline(), nil
 	default:
-		return s.getOtherRankFunction()
+		return otherPipeline, nil
 	}
 }
 
+func (s selection) explain(list []*models.SearchTorrentsResult) ([]Explanation, error) {
+	p, err := s.getPipeline()
+	if err != nil {
+		return nil, err
+	}
+	return s.scores(p, list), nil
+}
+
 func (s selection) log() *log.Entry {
 	if s.Log != nil {
 		return s.Log
@@ -1,26 +1,89 @@
 package selector
 
 import (
+	"errors"
 	"sort"
 
 	"github.com/RacoonMediaServer/rms-media-discovery/pkg/client/models"
 )
 
+// ErrNoCandidates means there is no torrent to select from: the list is empty or all candidates are blocked
+var ErrNoCandidates = errors.New("no candidates to select")
+
 type MediaSelector struct {
 	settings Settings
+	rules    compiledRules
+	rulesErr error
 }
 
 func New(settings Settings) MediaSelector {
-	return MediaSelector{settings: settings}
+	rules, err := settings.Rules.compile()
+	return MediaSelector{settings: settings, rules: rules, rulesErr: err}
 }
 
-func (s MediaSelector) Select(list []*models.SearchTorrentsResult, opts Options) *models.SearchTorrentsResult {
+// Rank returns rank of each item of the list according to the criteria
+func (s MediaSelector) Rank(list []*models.SearchTorrentsResult, opts Options) ([]float32, error) {
+	explanations, err := s.Explain(list, opts)
+	if err != nil {
+		return nil, err
+	}
+	ranks := make([]float32, len(list))
+	for i := range explanations {
+		ranks[i] = explanations[i].Total
+	}
+	return ranks, nil
+}
+
+// Explain returns breakdown of rank of each item of the list according to the criteria
+func (s MediaSelector) Explain(list []*models.SearchTorrentsResult, opts Options) ([]Explanation, error) {
 	selCtx := selection{
 		Settings: s.settings,
 		Options:  opts,
 	}
-	rank := selCtx.getRankFunction()
-	ranks := rank(list)
+	return selCtx.explain(list)
+}
+
+// Filter returns only candidates, which are allowed by the rules. Empty items and items without link are dropped
+func (s MediaSelector) Filter(list []*models.SearchTorrentsResult, opts Options) []*models.SearchTorrentsResult {
+	if s.rulesErr != nil && opts.Log != nil {
+		opts.Log.Warnf("Some rules are ignored: %s", s.rulesErr)
+	}
+	result := make([]*models.SearchTorrentsResult, 0, len(list))
+	for i, t := range list {
+		if t == nil {
+			continue
+		}
+		if t.Link == nil || *t.Link == "" {
+			if opts.Log != nil {
+				opts.Log.Debugf("%d has no link: %s", i, getString(t.Title))
+			}
+			continue
+		}
+		if s.rules.allowed(t) {
+			result = append(result, t)
+		} else if opts.Log != nil {
+			opts.Log.Debugf("%d blocked by rules: %s", i, getString(t.Title))
+		}
+	}
+	return result
+}
+
+// Select returns the best candidate of allowed by rules. ErrNoCandidates is returned, if nothing to choose from
+func (s MediaSelector) Select(list []*models.SearchTorrentsResult, opts Options) (*models.SearchTorrentsResult, error) {
+	sel, _, err := s.SelectRanked(list, opts)
+	return sel, err
+}
+
+// SelectRanked returns the best candidate like Select with its rank
+func (s MediaSelector) SelectRanked(list []*models.SearchTorrentsResult, opts Options) (*models.SearchTorrentsResult, float32, error) {
+	list = s.Filter(list, opts)
+	if len(list) == 0 {
+		return nil, 0, ErrNoCandidates
+	}
+	ranks, err := s.Rank(list, opts)
+	if err != nil {
+		return nil, 0, err
+	}
 	_, _, best := findMax(ranks, func(elem float32) float32 {
 		return elem
 	})
@@ -33,27 +96,37 @@ func (s MediaSelector) Select(list []*models.SearchTorrentsResult, opts Options)
 	if opts.Log != nil {
 		opts.Log.Infof("Selected { Title: %s, Voice: %s, Size: %d, Seeders: %d, Quality: %s }", getString(sel.Title), sel.Voice, getValue(sel.Size), getValue(sel.Seeders), sel.Quality)
 	}
-	return sel
+	return sel, ranks[best], nil
 }
 
-func (s MediaSelector) Sort(list []*models.SearchTorrentsResult, opts Options) {
-	selCtx := selection{
-		Settings: s.settings,
-		Options:  opts,
+// Sort orders the list by rank descending. Empty items are moved to the end
+func (s MediaSelector) Sort(list []*models.SearchTorrentsResult, opts Options) error {
+	ranks, err := s.Rank(list, opts)
+	if err != nil {
+		return err
 	}
-	rank := selCtx.getRankFunction()
-	ranks := rank(list)
 	if opts.Log != nil {
 		for i := range ranks {
 			opts.Log.Debugf("%d rank: %.4f", i, ranks[i])
 		}
 	}
-	ranksMap := map[string]float32{}
-	for i, r := range list {
-		ranksMap[*r.Link] = ranks[i]
-	}
 
-	sort.SliceStable(list, func(i, j int) bool {
-		return ranksMap[*list[j].Link] < ranksMap[*list[i].Link]
+	indexes := make([]int, len(list))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		left, right := indexes[i], indexes[j]
+		if list[left] == nil || list[right] == nil {
+			return list[right] == nil && list[left] != nil
+		}
+		return ranks[right] < ranks[left]
 	})
+
+	sorted := make([]*models.SearchTorrentsResult, len(list))
+	for i, index := range indexes {
+		sorted[i] = list[index]
+	}
+	copy(list, sorted)
+	return nil
 }
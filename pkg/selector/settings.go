@@ -8,4 +8,29 @@ type Settings struct {
 	VoiceList           Voices
 	QualityPrior        []string
 	AudioFormats        []string
+	Rules               Rules
+
+	// Pipelines override built-in movies ranking for the criteria
+	Pipelines map[Criteria]Pipeline
+
+	// Rankers are custom rank functions, which can be used in the pipelines
+	Rankers map[string]RankFunc `json:"-"`
+
+	// CodecPrior is a list of preferred video codecs (av1, hevc, avc)
+	CodecPrior []string
+
+	// Languages is an ordered list of preferred audio languages (rus, eng, original, ...). Overrides VoiceList
+	Languages []string
+
+	// RequireOriginal penalizes releases without original audio track
+	RequireOriginal bool
+
+	// SubtitleLanguages is an ordered list of preferred subtitles languages
+	SubtitleLanguages []string
+
+	// MinQuality is the lowest acceptable quality for the smallest criteria. Empty means any
+	MinQuality string
+
+	// Trackers contains reputations of the trackers
+	Trackers []TrackerReputation
 }
@@ -1,5 +1,7 @@
 package selector
 
+import "math"
+
 func getValue[T int | uint | int32 | uint32 | int64 | uint64](val *T) T {
 	if val == nil {
 		return T(0)
@@ -19,9 +21,11 @@ type ordered interface {
 	int | int8 | int16 | int32 | int64 | uint | uint8 | uint16 | uint32 | uint64 | float32 | float64
 }
 
+// findMax returns the max element, its value and index. For empty array it returns zero values and -1
 func findMax[T any, C ordered](arr []T, f func(elem T) C) (T, C, int) {
 	if len(arr) == 0 {
-		panic("array must be non empty")
+		var zero T
+		return zero, C(0), -1
 	}
 	max := arr[0]
 	index := 0
@@ -34,3 +38,19 @@ func findMax[T any, C ordered](arr []T, f func(elem T) C) (T, C, int) {
 
 	return max, f(max), index
 }
+
+// ratio normalizes the value by the max. Degenerate cases (zero, negative or non-finite max) give 0
+func ratio[T ordered](value, max T) float32 {
+	if max <= 0 {
+		return 0
+	}
+	r := float64(value) / float64(max)
+	if math.IsNaN(r) || math.IsInf(r, 0) {
+		return 0
+	}
+	return float32(r)
+}
+
+func isFinite(val float32) bool {
+	return !math.IsNaN(float64(val)) && !math.IsInf(float64(val), 0)
+}
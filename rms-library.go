@@ -2,17 +2,25 @@ package main
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/RacoonMediaServer/rms-library/v3/internal/analysis"
 	"github.com/RacoonMediaServer/rms-library/v3/internal/config"
 	"github.com/RacoonMediaServer/rms-library/v3/internal/db"
 	"github.com/RacoonMediaServer/rms-library/v3/internal/downloads"
 	"github.com/RacoonMediaServer/rms-library/v3/internal/lock"
 	"github.com/RacoonMediaServer/rms-library/v3/internal/migration"
 	"github.com/RacoonMediaServer/rms-library/v3/internal/schedule"
+	"github.com/RacoonMediaServer/rms-library/v3/internal/service/analyzer"
+	"github.com/RacoonMediaServer/rms-library/v3/internal/service/archive"
+	"github.com/RacoonMediaServer/rms-library/v3/internal/service/backup"
+	"github.com/RacoonMediaServer/rms-library/v3/internal/service/episodes"
 	"github.com/RacoonMediaServer/rms-library/v3/internal/service/lists"
 	"github.com/RacoonMediaServer/rms-library/v3/internal/service/movies"
+	"github.com/RacoonMediaServer/rms-library/v3/internal/service/rules"
 	"github.com/RacoonMediaServer/rms-library/v3/internal/service/torrents"
 	"github.com/RacoonMediaServer/rms-library/v3/internal/storage"
+	"github.com/RacoonMediaServer/rms-library/v3/pkg/api"
 	"github.com/RacoonMediaServer/rms-packages/pkg/pubsub"
 	rms_library "github.com/RacoonMediaServer/rms-packages/pkg/service/rms-library"
 	"github.com/RacoonMediaServer/rms-packages/pkg/service/servicemgr"
@@ -64,6 +72,13 @@ func main() {
 
 	cfg := config.Config()
 
+	if cfg.Analyzer.Rules != "" {
+		if err := analysis.LoadRules(cfg.Analyzer.Rules); err != nil {
+			logger.Fatalf("Load analyzer rules failed: %s", err)
+		}
+		logger.Infof("Analyzer rules loaded from %s", cfg.Analyzer.Rules)
+	}
+
 	database, err := db.Connect(cfg.Database)
 	if err != nil {
 		logger.Fatalf("Connect to database failed: %s", err)
@@ -100,6 +115,22 @@ func main() {
 	lk := lock.NewLocker()
 	sched := schedule.New()
 
+	if err = cfg.Ranking.Validate(); err != nil {
+		logger.Fatalf("Invalid ranking settings: %s", err)
+	}
+
+	if err = cfg.Rules.Validate(); err != nil {
+		logger.Fatalf("Invalid selection rules: %s", err)
+	}
+
+	archiveService := &archive.Service{
+		Database:        database,
+		Storage:         dirManager,
+		Scheduler:       sched,
+		CleanupInterval: time.Duration(cfg.Retention.CleanupIntervalHours) * time.Hour,
+	}
+	archiveService.Initialize()
+
 	settings := movies.Settings{
 		ServiceFactory:   f,
 		Database:         database,
@@ -110,6 +141,13 @@ func main() {
 		Scheduler:        sched,
 		Locker:           lk,
 		Publisher:        pubsub.NewPublisher(service),
+		Upgrade:          cfg.Upgrade,
+		Rules:            cfg.Rules,
+		Ranking:          cfg.Ranking,
+		Audio:            cfg.Audio,
+		Verification:     cfg.Verification,
+		Retention:        cfg.Retention,
+		Archive:          archiveService,
 	}
 
 	moviesService := movies.NewService(settings)
@@ -122,6 +160,7 @@ func main() {
 		Movies:    moviesService,
 		Scheduler: sched,
 		Downloads: downloadManager,
+		Archive:   archiveService,
 		Locker:    lk,
 	}
 
@@ -132,6 +171,26 @@ func main() {
 		Movies:    moviesService,
 	}
 
+	episodesService := &episodes.Service{
+		Database:  database,
+		Downloads: downloadManager,
+	}
+
+	rulesService := &rules.Service{
+		Database: database,
+	}
+
+	analyzerService := &analyzer.Service{}
+
+	backupService := &backup.Service{
+		Database:  database,
+		Storage:   dirManager,
+		Downloads: downloadManager,
+		Movies:    moviesService,
+		Locker:    lk,
+		Directory: cfg.Directories.Backup,
+	}
+
 	//регистрируем хендлеры
 	if err = rms_library.RegisterMoviesHandler(service.Server(), moviesService); err != nil {
 		logger.Fatalf("Register service failed: %s", err)
@@ -145,6 +204,30 @@ func main() {
 		logger.Fatalf("Register torrents service failed: %s", err)
 	}
 
+	if err = api.RegisterRankingHandler(service.Server(), torrentsService); err != nil {
+		logger.Fatalf("Register ranking service failed: %s", err)
+	}
+
+	if err = api.RegisterEpisodesHandler(service.Server(), episodesService); err != nil {
+		logger.Fatalf("Register episodes service failed: %s", err)
+	}
+
+	if err = api.RegisterRulesHandler(service.Server(), rulesService); err != nil {
+		logger.Fatalf("Register rules service failed: %s", err)
+	}
+
+	if err = api.RegisterAnalyzerHandler(service.Server(), analyzerService); err != nil {
+		logger.Fatalf("Register analyzer service failed: %s", err)
+	}
+
+	if err = api.RegisterArchiveHandler(service.Server(), archiveService); err != nil {
+		logger.Fatalf("Register archive service failed: %s", err)
+	}
+
+	if err = api.RegisterBackupHandler(service.Server(), backupService); err != nil {
+		logger.Fatalf("Register backup service failed: %s", err)
+	}
+
 	if err = service.Run(); err != nil {
 		logger.Fatalf("Run service failed: %s", err)
 	}